@@ -0,0 +1,29 @@
+package main
+
+import (
+	"time"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// iqrBounds 按 IQR 规则(Q1-1.5IQR, Q3+1.5IQR)计算耗时的正常范围
+func iqrBounds(sorted []time.Duration) (lo, hi time.Duration) {
+	q1 := runner.Quantile(sorted, 0.25)
+	q3 := runner.Quantile(sorted, 0.75)
+	iqr := q3 - q1
+	return q1 - iqr*3/2, q3 + iqr*3/2
+}
+
+// trimmedMean 去掉两端各 frac 比例的样本后求平均耗时
+func trimmedMean(sorted []time.Duration, frac float64) time.Duration {
+	k := int(float64(len(sorted)) * frac)
+	kept := sorted[k : len(sorted)-k]
+	if len(kept) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range kept {
+		total += d
+	}
+	return total / time.Duration(len(kept))
+}
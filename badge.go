@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// badge 是状态徽章的内容, JSON 形式即 shields.io 的 endpoint 格式
+type badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// newBadge 按本次运行的成功率生成徽章
+func newBadge(success, total int) badge {
+	b := badge{SchemaVersion: 1, Label: "uptime", Message: "no data", Color: "lightgrey"}
+	if total == 0 {
+		return b
+	}
+	up := float64(success) * 100 / float64(total)
+	b.Message = fmt.Sprintf("%.1f%%", up)
+	switch {
+	case up >= 99:
+		b.Color = "brightgreen"
+	case up >= 95:
+		b.Color = "green"
+	case up >= 90:
+		b.Color = "yellow"
+	case up >= 75:
+		b.Color = "orange"
+	default:
+		b.Color = "red"
+	}
+	return b
+}
+
+// badgeColors 把颜色名换算为 SVG 填充色
+var badgeColors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"lightgrey":   "#9f9f9f",
+}
+
+// svg 渲染扁平样式的徽章; 文字宽度按每字符 7 像素估算
+func (b badge) svg() string {
+	lw, mw := 10+7*len(b.Label), 10+7*len(b.Message)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">
+<rect width="%[2]d" height="20" fill="#555"/>
+<rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,sans-serif" font-size="11">
+<text x="%[7]d" y="14">%[4]s</text>
+<text x="%[8]d" y="14">%[5]s</text>
+</g>
+</svg>
+`, lw+mw, lw, mw, b.Label, b.Message, badgeColors[b.Color], lw/2, lw+mw/2)
+}
+
+// writeBadge 按扩展名把徽章写为 .json(shields.io endpoint) 或 .svg 文件
+func writeBadge(path string, b badge) error {
+	var data []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var err error
+		if data, err = json.Marshal(b); err != nil {
+			return err
+		}
+	case ".svg":
+		data = []byte(b.svg())
+	default:
+		return fmt.Errorf("徽章文件 %s 的扩展名应为 .json 或 .svg", path)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
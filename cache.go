@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// cacheProbe 是同一地址先后两次请求(冷/热)的结果
+type cacheProbe struct {
+	Cold, Warm         runner.HTTPResponse
+	ColdTime, WarmTime time.Duration
+}
+
+// cacheTask 构造先后请求 url 两次的任务; 超时作用于整对请求
+func cacheTask(e *runner.HTTPExecutor, url string) runner.Task[cacheProbe] {
+	return runner.Task[cacheProbe]{
+		URL: url,
+		Do: func(ctx context.Context) (cacheProbe, error) {
+			var p cacheProbe
+			start := time.Now()
+			cold, err := e.Do(ctx, url)
+			p.Cold, p.ColdTime = cold, time.Since(start)
+			if err != nil {
+				return p, err
+			}
+
+			start = time.Now()
+			warm, err := e.Do(ctx, url)
+			p.Warm, p.WarmTime = warm, time.Since(start)
+			return p, err
+		},
+	}
+}
+
+// 各家 CDN/代理报告缓存状态的响应头
+var cacheStatusHeaders = []string{"X-Cache", "CF-Cache-Status", "X-Cache-Status", "X-Proxy-Cache"}
+
+// cacheStatus 返回响应头中的缓存状态描述, 以及是否判定为命中
+func cacheStatus(h http.Header) (string, bool) {
+	for _, name := range cacheStatusHeaders {
+		if v := h.Get(name); v != "" {
+			return v, strings.Contains(strings.ToUpper(v), "HIT")
+		}
+	}
+	if age, err := strconv.Atoi(h.Get("Age")); err == nil && age > 0 {
+		return "Age " + h.Get("Age"), true
+	}
+	return "-", false
+}
+
+// cacheProblems 检查热请求的缓存头是否与实际缓存行为矛盾
+func cacheProblems(warm http.Header, hit bool) []string {
+	cc := strings.ToLower(warm.Get("Cache-Control"))
+	var problems []string
+	switch {
+	case cc == "" && warm.Get("Expires") == "":
+		problems = append(problems, "缺少 Cache-Control/Expires")
+	case hit && (strings.Contains(cc, "no-store") || strings.Contains(cc, "private")):
+		problems = append(problems, "声明 private/no-store 却被共享缓存命中")
+	case !hit && (strings.Contains(cc, "public") || strings.Contains(cc, "s-maxage")):
+		problems = append(problems, "声明可共享缓存但热请求未命中")
+	}
+	if hit && warm.Get("Set-Cookie") != "" {
+		problems = append(problems, "命中的缓存响应带 Set-Cookie")
+	}
+	return problems
+}
+
+// runCacheCheck 执行缓存分析模式并输出报告, 返回进程退出码
+func runCacheCheck(ctx context.Context, cfg runner.Config, e *runner.HTTPExecutor, urls []string, out io.Writer) int {
+	tasks := make([]runner.Task[cacheProbe], len(urls))
+	for i, url := range urls {
+		tasks[i] = cacheTask(e, url)
+	}
+	r := &runner.Runner[cacheProbe]{Config: cfg}
+	results, err := r.RunWithContext(ctx, tasks)
+
+	fmt.Fprintln(out, "\n======================= 缓存分析(冷/热请求) =======================")
+	fmt.Fprintf(out, "%-5s %-12s %-12s %-12s %-20s %-45s %s\n", "序号", "冷请求", "热请求", "差值", "缓存状态", "请求地址", "问题")
+	fmt.Fprintln(out, "----------------------------------------------------------------------")
+
+	var ok, hits, problemCount int
+	var coldTotal, warmTotal time.Duration
+	for i, res := range results {
+		p := res.Value
+		if res.Err != nil {
+			fmt.Fprintf(out, "%-5d %-12s %-12s %-12s %-20s %-45s ❌ %v\n", i, "-", "-", "-", "-", res.URL, res.Err)
+			continue
+		}
+		ok++
+		coldTotal += p.ColdTime
+		warmTotal += p.WarmTime
+
+		status, hit := cacheStatus(p.Warm.Header)
+		if hit {
+			hits++
+		}
+		problems := cacheProblems(p.Warm.Header, hit)
+		problemCount += len(problems)
+		detail := "✅"
+		if len(problems) > 0 {
+			detail = "⚠️ " + strings.Join(problems, "; ")
+		}
+		fmt.Fprintf(out, "%-5d %-12v %-12v %-12v %-20s %-45s %s\n",
+			i, p.ColdTime.Round(time.Microsecond), p.WarmTime.Round(time.Microsecond),
+			(p.WarmTime - p.ColdTime).Round(time.Microsecond), status, res.URL, detail)
+	}
+
+	fmt.Fprintln(out, "\n======================= 缓存统计 =======================")
+	fmt.Fprintf(out, "地址数: %d (成功 %d)\n", len(urls), ok)
+	if ok > 0 {
+		fmt.Fprintf(out, "热请求命中率: %.1f%% (%d/%d)\n", float64(hits)*100/float64(ok), hits, ok)
+		fmt.Fprintf(out, "平均耗时: 冷 %v, 热 %v\n", coldTotal/time.Duration(ok), warmTotal/time.Duration(ok))
+	}
+	fmt.Fprintf(out, "缓存头问题: %d 个\n", problemCount)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⛔ %v\n", err)
+		return 1
+	}
+	return 0
+}
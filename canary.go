@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"time"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// 金丝雀分析的两个分组
+const (
+	armBaseline = "基线"
+	armCanary   = "金丝雀"
+)
+
+// canaryPlan 是金丝雀模式的参数
+type canaryPlan struct {
+	baseline string // 基线的基础地址, 为空时使用请求列表中的原始地址
+	canary   string // 金丝雀的基础地址
+	percent  int    // 发往金丝雀的流量百分比
+	requests int    // 每个地址发出的请求数
+}
+
+// rebase 把 rawURL 的协议与主机换成 base 的, base 带路径时作为前缀; base 为空时原样返回
+func rebase(rawURL, base string) (string, error) {
+	if base == "" {
+		return rawURL, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	b, err := url.Parse(base)
+	if err != nil || b.Host == "" {
+		return "", fmt.Errorf("无效的基础地址: %s", base)
+	}
+	u.Scheme, u.Host = b.Scheme, b.Host
+	if b.Path != "" && b.Path != "/" {
+		u.Path = path.Join(b.Path, u.Path)
+	}
+	return u.String(), nil
+}
+
+// toCanary 报告某地址的第 j 个请求是否发往金丝雀; 按比例均匀穿插, 每 100 个请求中恰好 percent 个
+func toCanary(j, percent int) bool {
+	return (j+1)*percent/100 > j*percent/100
+}
+
+// armStats 是一个分组(或分组内一个地址)的结果汇总
+type armStats struct {
+	results []runner.Result[runner.HTTPResponse]
+	errors  int
+}
+
+func (a *armStats) add(r runner.Result[runner.HTTPResponse]) {
+	a.results = append(a.results, r)
+	if r.Err != nil {
+		a.errors++
+	}
+}
+
+// errorRate 返回出错比例(百分比), 没有请求时为 0
+func (a *armStats) errorRate() float64 {
+	if len(a.results) == 0 {
+		return 0
+	}
+	return float64(a.errors) * 100 / float64(len(a.results))
+}
+
+// runCanary 按比例把每个地址的请求分发到基线与金丝雀, 分组对比错误率与耗时分位;
+// 金丝雀错误率高于基线时返回 1, 便于在发布流水线中作为门禁
+func runCanary(ctx context.Context, cfg runner.Config, e *runner.HTTPExecutor, specs []urlSpec, plan canaryPlan, out io.Writer) int {
+	var tasks []runner.Task[runner.HTTPResponse]
+	var arms []string
+	var origins []int // 每个任务对应的请求列表序号
+	for i, spec := range specs {
+		baseURL, err := rebase(spec.URL, plan.baseline)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		canaryURL, err := rebase(spec.URL, plan.canary)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		exec := e.With(spec.Method, spec.Header)
+		for j := 0; j < plan.requests; j++ {
+			arm, target := armBaseline, baseURL
+			if toCanary(j, plan.percent) {
+				arm, target = armCanary, canaryURL
+			}
+			tasks = append(tasks, exec.Task(target))
+			arms = append(arms, arm)
+			origins = append(origins, i)
+		}
+	}
+
+	r := &runner.Runner[runner.HTTPResponse]{Config: cfg}
+	results, err := r.RunWithContext(ctx, tasks)
+
+	total := map[string]*armStats{armBaseline: {}, armCanary: {}}
+	perURL := make([]map[string]*armStats, len(specs))
+	for i := range perURL {
+		perURL[i] = map[string]*armStats{armBaseline: {}, armCanary: {}}
+	}
+	for i, res := range results {
+		total[arms[i]].add(res)
+		perURL[origins[i]][arms[i]].add(res)
+	}
+
+	fmt.Fprintf(out, "\n======================= 金丝雀分析(%d%% 流量) =======================\n", plan.percent)
+	fmt.Fprintf(out, "%-8s %-8s %-10s %-12s %-12s %-12s %s\n", "分组", "请求数", "错误率", "p50", "p90", "p99", "均值")
+	fmt.Fprintln(out, "----------------------------------------------------------------------")
+	for _, arm := range []string{armBaseline, armCanary} {
+		a := total[arm]
+		s := runner.ComputeStats(a.results)
+		fmt.Fprintf(out, "%-8s %-8d %-10s %-12v %-12v %-12v %v\n", arm, len(a.results),
+			fmt.Sprintf("%.1f%%", a.errorRate()), s.P50.Round(time.Microsecond), s.P90.Round(time.Microsecond),
+			s.P99.Round(time.Microsecond), s.Mean.Round(time.Microsecond))
+	}
+
+	fmt.Fprintln(out, "\n按地址对比(金丝雀 - 基线):")
+	for i, spec := range specs {
+		base, canary := perURL[i][armBaseline], perURL[i][armCanary]
+		bs, cs := runner.ComputeStats(base.results), runner.ComputeStats(canary.results)
+		mark := "✅"
+		if canary.errorRate() > base.errorRate() {
+			mark = "⚠️"
+		}
+		delta := (cs.P50 - bs.P50).Round(time.Microsecond)
+		sign := ""
+		if delta >= 0 {
+			sign = "+"
+		}
+		fmt.Fprintf(out, "  %s %-45s 错误率 %+.1f%%, p50 %s%v\n", mark, spec.URL,
+			canary.errorRate()-base.errorRate(), sign, delta)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⛔ %v\n", err)
+		return 1
+	}
+	// 某一组没有结果时无从比较, 不能当作通过
+	for _, arm := range []string{armBaseline, armCanary} {
+		if len(total[arm].results) == 0 {
+			fmt.Fprintf(out, "⛔ %s组没有请求, 无法比较\n", arm)
+			return 1
+		}
+	}
+	if total[armCanary].errorRate() > total[armBaseline].errorRate() {
+		fmt.Fprintf(out, "⚠️ 金丝雀错误率 %.1f%% 高于基线 %.1f%%\n", total[armCanary].errorRate(), total[armBaseline].errorRate())
+		return 1
+	}
+	return 0
+}
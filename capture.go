@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// bodySampler 把被采样保留的响应体逐个写入目录, 文件名为 <序号>-<状态码>.body
+type bodySampler struct {
+	dir   string
+	saved int
+	err   error // 首个写入错误
+}
+
+func newBodySampler(dir string) (*bodySampler, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &bodySampler{dir: dir}, nil
+}
+
+// save 写出 r 中保留的响应体, 未被采样的结果直接跳过
+func (s *bodySampler) save(r runner.Result[runner.HTTPResponse]) {
+	if r.Value.Body == nil || s.err != nil {
+		return
+	}
+	name := fmt.Sprintf("%d-%d.body", r.Index, r.Value.StatusCode)
+	if s.err = os.WriteFile(filepath.Join(s.dir, name), r.Value.Body, 0o644); s.err == nil {
+		s.saved++
+	}
+}
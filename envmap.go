@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// envTable 是按环境划分的服务地址表: 环境名 -> 逻辑服务名 -> 基础地址, 例如
+//
+//	{"staging": {"payments": "https://payments.staging.example.com"},
+//	 "prod":    {"payments": "https://payments.example.com"}}
+type envTable map[string]map[string]string
+
+func loadEnvTable(path string) (envTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t envTable
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("解析环境表 %s 失败: %w", path, err)
+	}
+	return t, nil
+}
+
+// hosts 返回环境 env 的服务地址表
+func (t envTable) hosts(env string) (map[string]string, error) {
+	hosts, ok := t[env]
+	if !ok {
+		names := make([]string, 0, len(t))
+		for name := range t {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("环境表中没有 %q, 可选 %s", env, strings.Join(names, "、"))
+	}
+	return hosts, nil
+}
+
+// logicalService 判断 raw 是否为 "服务名:/路径" 形式的逻辑地址, 返回服务名与路径
+func logicalService(raw string) (service, path string, ok bool) {
+	service, path, ok = strings.Cut(raw, ":")
+	if !ok || service == "" || !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") {
+		return "", "", false
+	}
+	return service, path, true
+}
+
+// resolveURL 把逻辑地址解析为 hosts 中对应服务的基础地址加路径, 普通地址原样返回
+func resolveURL(raw string, hosts map[string]string) (string, error) {
+	service, path, ok := logicalService(raw)
+	if !ok {
+		return raw, nil
+	}
+	if hosts == nil {
+		return "", fmt.Errorf("%s 是逻辑地址, 需要用 -env 选择环境", raw)
+	}
+	base, ok := hosts[service]
+	if !ok {
+		return "", fmt.Errorf("当前环境没有服务 %q (%s)", service, raw)
+	}
+	return strings.TrimSuffix(base, "/") + path, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// headerCheck 是一项安全响应头检查; check 返回是否通过, https 表示仅对 HTTPS 响应检查
+type headerCheck struct {
+	name  string
+	https bool
+	check func(h http.Header) bool
+}
+
+var versionPattern = regexp.MustCompile(`\d+\.\d+`)
+
+var headerChecks = []headerCheck{
+	{"HSTS", true, func(h http.Header) bool {
+		// max-age 至少半年才有实际保护作用
+		for _, part := range strings.Split(h.Get("Strict-Transport-Security"), ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(strings.ToLower(part)), "max-age="); ok {
+				age, err := strconv.Atoi(v)
+				return err == nil && age >= 180*24*3600
+			}
+		}
+		return false
+	}},
+	{"CSP", false, func(h http.Header) bool {
+		return h.Get("Content-Security-Policy") != ""
+	}},
+	{"X-Content-Type-Options", false, func(h http.Header) bool {
+		return strings.EqualFold(h.Get("X-Content-Type-Options"), "nosniff")
+	}},
+	{"X-Frame-Options", false, func(h http.Header) bool {
+		// CSP 的 frame-ancestors 同样能防止点击劫持
+		xfo := strings.ToUpper(h.Get("X-Frame-Options"))
+		return xfo == "DENY" || xfo == "SAMEORIGIN" || strings.Contains(h.Get("Content-Security-Policy"), "frame-ancestors")
+	}},
+	{"Referrer-Policy", false, func(h http.Header) bool {
+		return h.Get("Referrer-Policy") != ""
+	}},
+	{"Permissions-Policy", false, func(h http.Header) bool {
+		return h.Get("Permissions-Policy") != ""
+	}},
+	{"Server/X-Powered-By", false, func(h http.Header) bool {
+		// 不应暴露服务端软件版本
+		return !versionPattern.MatchString(h.Get("Server")) && h.Get("X-Powered-By") == ""
+	}},
+}
+
+// hostScore 汇总同一主机所有响应的检查结果
+type hostScore struct {
+	responses int
+	passed    map[string]int // 检查项 -> 通过的响应数
+	checked   map[string]int // 检查项 -> 参与检查的响应数
+}
+
+// score 返回通过率(0~100)
+func (s *hostScore) score() float64 {
+	var passed, checked int
+	for name, n := range s.checked {
+		passed += s.passed[name]
+		checked += n
+	}
+	if checked == 0 {
+		return 0
+	}
+	return float64(passed) * 100 / float64(checked)
+}
+
+func grade(score float64) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 75:
+		return "B"
+	case score >= 60:
+		return "C"
+	case score >= 40:
+		return "D"
+	}
+	return "F"
+}
+
+// runHeaderAudit 请求所有地址并按主机给出安全响应头评分, 返回进程退出码
+func runHeaderAudit(ctx context.Context, cfg runner.Config, tasks []runner.Task[runner.HTTPResponse], out io.Writer) int {
+	r := &runner.Runner[runner.HTTPResponse]{Config: cfg}
+	results, err := r.RunWithContext(ctx, tasks)
+
+	hosts := make(map[string]*hostScore)
+	var unreachable int
+	for _, res := range results {
+		if res.Value.Header == nil {
+			unreachable++
+			continue
+		}
+		host, https := res.URL, false
+		if u, err := url.Parse(res.URL); err == nil {
+			host, https = u.Host, u.Scheme == "https"
+		}
+		s := hosts[host]
+		if s == nil {
+			s = &hostScore{passed: make(map[string]int), checked: make(map[string]int)}
+			hosts[host] = s
+		}
+		s.responses++
+		for _, c := range headerChecks {
+			if c.https && !https {
+				continue
+			}
+			s.checked[c.name]++
+			if c.check(res.Value.Header) {
+				s.passed[c.name]++
+			}
+		}
+	}
+
+	names := make([]string, 0, len(hosts))
+	for host := range hosts {
+		names = append(names, host)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(out, "\n======================= 安全响应头评分(按主机) =======================")
+	for _, host := range names {
+		s := hosts[host]
+		fmt.Fprintf(out, "%s  评级 %s (%.0f 分, %d 个响应)\n", host, grade(s.score()), s.score(), s.responses)
+		for _, c := range headerChecks {
+			checked := s.checked[c.name]
+			if checked == 0 {
+				continue
+			}
+			mark := "✅"
+			if s.passed[c.name] < checked {
+				mark = "❌"
+			}
+			fmt.Fprintf(out, "  %s %-24s %d/%d\n", mark, c.name, s.passed[c.name], checked)
+		}
+	}
+
+	fmt.Fprintln(out, "\n======================= 审计统计 =======================")
+	fmt.Fprintf(out, "请求数: %d, 主机数: %d, 无响应 %d\n", len(results), len(hosts), unreachable)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⛔ %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// 链接检查的分类
+const (
+	linkOK         = "正常"
+	linkRedirected = "重定向"
+	linkBroken     = "失效"
+	linkTimeout    = "超时"
+	linkError      = "无法访问"
+)
+
+// linkVerdict 把一个结果归入链接检查的分类, 并给出"最终状态"的描述
+func linkVerdict(r runner.Result[runner.HTTPResponse]) (category, final string) {
+	v := r.Value
+	switch {
+	case r.Status == runner.StatusTimeout, errors.Is(r.Err, context.DeadlineExceeded):
+		return linkTimeout, "超时"
+	case v.StatusCode == 0:
+		return linkError, r.Status
+	case v.StatusCode >= 400:
+		final = fmt.Sprintf("HTTP %d", v.StatusCode)
+		if v.Redirects > 0 {
+			final = fmt.Sprintf("%d 次重定向 → HTTP %d", v.Redirects, v.StatusCode)
+		}
+		return linkBroken, final
+	case v.Redirects > 0:
+		return linkRedirected, fmt.Sprintf("%d 次重定向 → HTTP %d", v.Redirects, v.StatusCode)
+	}
+	return linkOK, fmt.Sprintf("HTTP %d", v.StatusCode)
+}
+
+// runLinkCheck 是链接失效检查预设: 跟随重定向到最终地址, 按最终状态分类,
+// 输出分类统计、状态码分布和失效链接清单(输入序号 → 最终状态), 返回进程退出码
+func runLinkCheck(ctx context.Context, cfg runner.Config, tasks []runner.Task[runner.HTTPResponse], out io.Writer) int {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second // 失效链接常表现为挂起, 不设超时会拖住整批
+	}
+	r := &runner.Runner[runner.HTTPResponse]{Config: cfg}
+	results, err := r.RunWithContext(ctx, tasks)
+
+	counts := make(map[string]int)
+	codes := make(map[int]int)
+	fmt.Fprintln(out, "\n======================= 链接检查 =======================")
+	fmt.Fprintf(out, "%-5s %-8s %-24s %s\n", "序号", "分类", "最终状态", "请求地址")
+	fmt.Fprintln(out, "----------------------------------------------------------------------")
+	for _, res := range results {
+		category, final := linkVerdict(res)
+		counts[category]++
+		if res.Value.StatusCode != 0 {
+			codes[res.Value.StatusCode]++
+		}
+		if category == linkOK {
+			continue
+		}
+		fmt.Fprintf(out, "%-5d %-8s %-24s %s\n", res.Index, category, final, res.URL)
+		if category == linkRedirected || (category == linkBroken && res.Value.Redirects > 0) {
+			fmt.Fprintf(out, "%-5s %-8s %-24s → %s\n", "", "", "", res.Value.FinalURL)
+		}
+	}
+
+	fmt.Fprintln(out, "\n======================= 分类统计 =======================")
+	fmt.Fprintf(out, "链接数: %d\n", len(results))
+	for _, category := range []string{linkOK, linkRedirected, linkBroken, linkTimeout, linkError} {
+		if counts[category] > 0 {
+			fmt.Fprintf(out, "%s: %d\n", category, counts[category])
+		}
+	}
+	statusCodes := make([]int, 0, len(codes))
+	for code := range codes {
+		statusCodes = append(statusCodes, code)
+	}
+	sort.Ints(statusCodes)
+	fmt.Fprint(out, "最终状态码:")
+	for _, code := range statusCodes {
+		fmt.Fprintf(out, " %d×%d", code, codes[code])
+	}
+	fmt.Fprintln(out)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⛔ %v\n", err)
+		return 1
+	}
+	if counts[linkBroken]+counts[linkTimeout]+counts[linkError] > 0 {
+		return 1
+	}
+	return 0
+}
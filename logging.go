@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// newLogger 按级别和格式创建写到 w 的结构化日志; level 为 off 时返回 nil(不记录)
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	if level == "off" {
+		return nil, nil
+	}
+	var lv slog.Level
+	if err := lv.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("未知的日志级别 %q, 可选 off、debug、info、warn、error", level)
+	}
+	opts := &slog.HandlerOptions{Level: lv}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("未知的日志格式 %q, 可选 text、json", format)
+}
@@ -1,173 +1,461 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"math/rand"
-	"sort"
-	"sync"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/abnerCrack/go-routine/runner"
 )
 
-type Result struct {
-	Response string
-	Err      error
-	Index    int    // 请求顺序索引
-	URL      string // 原始URL
-	Status   string // 状态标识
-	Duration time.Duration
+// demoURLs 是未指定URL时使用的演示列表
+var demoURLs = []string{
+	"https://api.service.com/user",
+	"https://api.service.com/products",
+	"https://api.service.com/orders",
+	"https://api.service.com/inventory",
+	"https://api.service.com/payments",
+	"https://api.service.com/shipping",
+	"https://api.service.com/reviews",
+	"https://api.service.com/analytics",
+	"https://api.service.com/notifications",
+	"https://api.service.com/recommendations",
 }
 
-func mockRequest(url string, index int, wg *sync.WaitGroup, resultChan chan<- Result) {
-	defer wg.Done()
+func main() {
+	os.Exit(run())
+}
+
+// run 执行一次完整的运行并返回进程退出码
+func run() int {
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		printVersion(os.Stdout)
+		return 0
+	}
 
-	//start := time.Now()
-	delay := time.Duration(rand.Intn(1000)) * time.Millisecond
-	time.Sleep(delay)
+	mock := flag.Bool("mock", false, "使用模拟请求代替真实 HTTP 请求(演示用)")
+	urlsPath := flag.String("urls", "", "从文件读取请求列表(\"-\" 表示标准输入), 每行 [METHOD] URL [Name:value ...], 与命令行参数合并")
+	envName := flag.String("env", "", "目标环境: 把 \"服务名:/路径\" 形式的逻辑地址解析为 -env-file 中该环境的服务地址")
+	envFile := flag.String("env-file", "envs.json", "环境表文件(JSON: 环境名 -> 服务名 -> 基础地址)")
+	method := flag.String("method", "GET", "HTTP 请求方法")
+	body := flag.String("body", "", "HTTP 请求体")
+	captureDir := flag.String("capture-dir", "", "响应体采样: 把被采样的完整响应体写入该目录(失败响应总是保留), 为空表示不保留")
+	captureRate := flag.Float64("capture-rate", 0.01, "响应体采样中成功响应的采样比例(0~1)")
+	captureMax := flag.Int("capture-max", 1<<20, "每个响应最多保留的字节数, 0 表示不限")
+	requestIDHeader := flag.String("request-id-header", "", "发送唯一请求 ID 的头名称(如 X-Request-ID), 并校验响应是否回显; 为空表示不启用")
+	encoding := flag.String("encoding", "", "请求体压缩算法: gzip 或 deflate(zlib 格式), 为空表示不压缩; zstd 等算法仅在库中通过 runner.RegisterEncoding 注册后可用")
+	acceptEncoding := flag.String("accept-encoding", "", "原样发送的 Accept-Encoding(如 \"br, zstd\"), 响应不再透明解压; 为空时自动协商 gzip")
+	ua := flag.String("user-agent", userAgent(), "请求的 User-Agent")
+	timeout := flag.Duration("timeout", 0, "单个请求的超时时间(如 2s), 0 表示不限")
+	deadlineHeaders := flag.Bool("deadline-headers", false, "按 -timeout 剩余预算发送 X-Request-Timeout 与 grpc-timeout 请求头")
+	retries := flag.Int("retries", 0, "失败或超时请求的最大重试次数")
+	retryDelay := flag.Duration("retry-delay", 100*time.Millisecond, "首次重试前的等待, 之后指数翻倍")
+	retryMaxDelay := flag.Duration("retry-max-delay", 5*time.Second, "单次重试等待的上限")
+	retryJitter := flag.Float64("retry-jitter", 0.2, "重试等待的随机抖动比例(0~1)")
+	failFast := flag.Bool("fail-fast", false, "快速失败: 任一请求出错即取消其余请求, 退出码为 1")
+	rate := flag.Int("rate", 0, "限流: 每 -rate-per 时长最多启动的请求数, 0 表示不限流")
+	ratePer := flag.Duration("rate-per", time.Second, "限流的时间单位")
+	rateAlgo := flag.String("rate-algo", runner.AlgoTokenBucket, "限流算法: token、leaky、sliding、gcra")
+	burst := flag.Int("burst", 1, "令牌桶/GCRA 允许的突发请求数")
+	throttleK := flag.Float64("adaptive-throttle", 0, "按主机的客户端自适应限流倍数 K(通常取 2), 0 表示不启用")
+	breakerThreshold := flag.Int("breaker", 0, "断路器: 同一主机连续出错该次数后短路后续请求, 0 表示不启用")
+	breakerCooldown := flag.Duration("breaker-cooldown", 10*time.Second, "断路器打开后的冷却时间")
+	maxGoroutines := flag.Int("max-goroutines", 0, "资源保护: 进程协程数上限, 接近时暂停分发(没有在途请求时总是放行, 低于启动时的基线则按基线放宽), 0 表示不检查")
+	maxFDs := flag.Int("max-fds", 0, "资源保护: 打开的文件描述符上限, 0 表示不检查")
+	maxMemoryMB := flag.Int("max-memory", 0, "资源保护: 内存占用估算上限(MiB), 0 表示不检查")
+	autoReduce := flag.Bool("auto-reduce", false, "遇到本机文件描述符/端口耗尽时自动把并发减半")
+	concurrency := flag.Int("concurrency", 0, "同时执行的最大请求数, 0 表示每个请求一个协程")
+	maxPerHost := flag.Int("max-per-host", 0, "同一主机同时执行的最大请求数, 0 表示不限")
+	strictMode := flag.Bool("strict", false, "严格模式: 运行时校验 WaitGroup/通道不变量, 违反时 panic")
+	showBar := flag.Bool("progress-bar", false, "在标准错误上显示实时进度条(完成数、运行中、出错数、预计剩余时间)")
+	progressDest := flag.String("progress", "stdout", "实时进度输出: stdout、stderr、none 或文件路径")
+	outDest := flag.String("out", "stdout", "最终报告输出: stdout、stderr 或文件路径")
+	csvPath := flag.String("csv", "", "把最终结果(序号、地址、状态、耗时、次数、错误)另存为 CSV 文件")
+	badgePath := flag.String("badge", "", "运行结束后写出状态徽章: .json(shields.io endpoint 格式) 或 .svg 文件")
+	logLevel := flag.String("log-level", "off", "结构化日志(写到标准错误)的级别: off、debug、info、warn、error; 记录 task_start、retry、task_done、task_cancel 事件")
+	logFormat := flag.String("log-format", "text", "结构化日志格式: text 或 json")
+	otelEnabled := flag.Bool("otel", false, "启用 OpenTelemetry 追踪: 每次运行一个父 span、每个请求一个子 span, 经 OTLP/HTTP 导出(地址见 OTEL_EXPORTER_OTLP_ENDPOINT)")
+	format := flag.String("output", formatText, "最终报告格式: text、json(完整报告对象)、ndjson(每完成一个请求输出一行结果) 或 none(不输出, 配合 -csv、-badge 使用); json 报告写到标准输出时不再在标准输出打印实时进度")
+	printEvery := flag.Int("print-every", 1, "实时输出中每 N 条结果输出一条")
+	printOnly := flag.String("print-only", "all", "实时输出的结果范围: all 或 failures")
+	jitterWindow := flag.Duration("jitter", 0, "每个请求启动前的随机抖动窗口(如 200ms), 避免同时发出")
+	cacheCheck := flag.Bool("cache-check", false, "缓存分析模式: 对每个地址先后发出冷/热两次请求, 分析 Age、X-Cache 与耗时差异")
+	tlsAuditMode := flag.Bool("tls-audit", false, "TLS 审计模式: 检查所有 HTTPS 主机的证书过期、弱签名算法、主机名与信任链")
+	tlsWarnDays := flag.Int("tls-warn-days", 30, "TLS 审计中证书剩余有效期少于该天数时告警")
+	headerAuditMode := flag.Bool("header-audit", false, "安全响应头审计模式: 检查 HSTS、CSP、X-Content-Type-Options 等并按主机评分")
+	linkCheckMode := flag.Bool("link-check", false, "链接失效检查预设: 跟随重定向, 按最终状态(正常/重定向/失效/超时)分类输出失效链接清单")
+	canaryBase := flag.String("canary", "", "金丝雀分析模式: 金丝雀的基础地址(如 https://canary.example.com), 按 -canary-percent 把每个地址的部分请求改发到该主机")
+	baselineBase := flag.String("baseline", "", "金丝雀分析中基线的基础地址, 为空时使用请求列表中的原始地址")
+	canaryPercent := flag.Int("canary-percent", 10, "金丝雀分析中发往金丝雀的流量百分比(1~99)")
+	canaryRequests := flag.Int("canary-requests", 20, "金丝雀分析中每个地址发出的请求数, 其中 请求数*百分比/100 个发往金丝雀(两组都须至少一个)")
+	deadline := flag.Duration("deadline", 0, "整批运行的截止时间(如 30s), 到期后取消未完成的请求并标记为\"未完成\", 0 表示不限")
+	raceFirst := flag.Bool("race-first", false, "竞速模式: 同一请求发往多个冗余端点, 任一成功即取消其余请求, 报告胜者与落败请求取消前的运行时长")
+	drain := flag.Duration("drain", 5*time.Second, "收到 SIGINT/SIGTERM 后等待运行中请求完成的最长时间, 超时后取消它们并输出已完成部分的报告")
+	trim := flag.Float64("trim", 0, "截尾比例(0~0.5), 大于 0 时额外报告去掉两端各该比例样本后的平均耗时")
+	flag.Parse()
 
-	result := Result{
-		Index:    index,
-		URL:      url,
-		Duration: delay,
+	if (*cacheCheck || *headerAuditMode || *linkCheckMode || *canaryBase != "") && *mock {
+		fmt.Fprintln(os.Stderr, "-cache-check、-header-audit、-link-check 与 -canary 需要真实请求, 不能与 -mock 同时使用")
+		return 2
+	}
+	if *captureDir != "" && (*captureRate < 0 || *captureRate > 1) {
+		fmt.Fprintln(os.Stderr, "-capture-rate 取值需在 [0, 1] 之间")
+		return 2
+	}
+	if *canaryBase != "" {
+		// 每个地址发往金丝雀的请求数为 requests*percent/100(向下取整), 两组都至少要有一个请求
+		canaryN := *canaryRequests * *canaryPercent / 100
+		if *canaryPercent < 1 || *canaryPercent > 99 || canaryN < 1 || canaryN >= *canaryRequests {
+			fmt.Fprintf(os.Stderr, "-canary-percent %d 与 -canary-requests %d 使某一组没有请求, 需满足 1 <= 请求数*百分比/100 < 请求数\n",
+				*canaryPercent, *canaryRequests)
+			return 2
+		}
+	}
+	switch *format {
+	case formatText, formatJSON, formatNDJSON, formatNone:
+	default:
+		fmt.Fprintf(os.Stderr, "未知的报告格式 %q, 可选 text、json、ndjson、none\n", *format)
+		return 2
+	}
+	if ext := strings.ToLower(filepath.Ext(*badgePath)); *badgePath != "" && ext != ".json" && ext != ".svg" {
+		fmt.Fprintf(os.Stderr, "-badge 文件的扩展名应为 .json 或 .svg: %s\n", *badgePath)
+		return 2
+	}
+	logger, err := newLogger(os.Stderr, *logLevel, *logFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *encoding != "" && !slices.Contains(runner.Encodings(), *encoding) {
+		fmt.Fprintf(os.Stderr, "未知的压缩算法 %q, 可选 %s\n", *encoding, strings.Join(runner.Encodings(), "、"))
+		return 2
+	}
+	if *trim < 0 || *trim >= 0.5 {
+		fmt.Fprintln(os.Stderr, "-trim 取值需在 [0, 0.5) 之间")
+		return 2
 	}
 
-	if rand.Intn(10) < 2 {
-		result.Status = "失败"
-		result.Err = fmt.Errorf("请求失败 [%s] (耗时: %v)", url, delay)
-	} else {
-		result.Status = "成功"
-		result.Response = fmt.Sprintf("结果数据 [%s]", url[:7])
+	var limiter runner.Limiter
+	if *rate > 0 {
+		l, err := runner.NewLimiter(*rateAlgo, *rate, *ratePer, *burst)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		limiter = l
 	}
 
-	resultChan <- result
-}
+	var throttle *runner.AdaptiveThrottle
+	if *throttleK > 0 {
+		throttle = runner.NewAdaptiveThrottle(*throttleK)
+	}
+	var resources *runner.ResourceLimits
+	if *maxGoroutines > 0 || *maxFDs > 0 || *maxMemoryMB > 0 {
+		resources = &runner.ResourceLimits{
+			MaxGoroutines: *maxGoroutines,
+			MaxOpenFiles:  *maxFDs,
+			MaxMemory:     uint64(*maxMemoryMB) << 20,
+		}
+	}
+	var breaker *runner.CircuitBreaker
+	if *breakerThreshold > 0 {
+		breaker = runner.NewCircuitBreaker(*breakerThreshold, *breakerCooldown)
+	}
+
+	completedFilter, err := newPrintFilter(*printEvery, *printOnly)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	orderedFilter, _ := newPrintFilter(*printEvery, *printOnly)
+
+	progress, closeProgress, err := openOutput(*progressDest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "打开进度输出失败: %v\n", err)
+		return 1
+	}
+	defer closeProgress()
+
+	out, closeOut, err := openOutput(*outDest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "打开报告输出失败: %v\n", err)
+		return 1
+	}
+	defer closeOut()
+
+	// 机器可读的报告与实时进度同在标准输出时, 丢弃进度以免破坏 JSON
+	if (*format == formatJSON || *format == formatNDJSON) && out == os.Stdout && progress == os.Stdout {
+		progress = io.Discard
+	}
 
-func main() {
 	rand.Seed(time.Now().UnixNano())
 
-	// 1. 创建有序的URL列表(带序号)
-	urls := []string{
-		"https://api.service.com/user",
-		"https://api.service.com/products",
-		"https://api.service.com/orders",
-		"https://api.service.com/inventory",
-		"https://api.service.com/payments",
-		"https://api.service.com/shipping",
-		"https://api.service.com/reviews",
-		"https://api.service.com/analytics",
-		"https://api.service.com/notifications",
-		"https://api.service.com/recommendations",
-	}
-
-	// 2. 创建带缓冲的结果通道(双倍容量)
-	resultChan := make(chan Result, len(urls)*2)
-
-	// 3. 使用WaitGroup确保所有请求完成
-	var wg sync.WaitGroup
-	totalStart := time.Now()
-
-	// 4. 启动所有并发请求(携带索引序号)
-	for i, url := range urls {
-		wg.Add(1)
-		go mockRequest(url, i, &wg, resultChan)
-	}
-
-	// 5. 后台聚合结果(使用索引确保顺序)
-	results := make([]Result, len(urls))
-	aggregateDone := make(chan struct{})
-
-	go func() {
-		defer close(aggregateDone)
-		wg.Wait()
-		close(resultChan) // 确保所有结果已发送
-	}()
-
-	// 6. 按完成顺序接收结果(立即显示)
-	fmt.Println("开始并发请求...")
-	fmt.Printf("%-5s %-12s %-8s %-45s %s\n", "序号", "耗时", "状态", "请求地址", "详情")
-	fmt.Println("----------------------------------------------------------------------")
-
-	// 创建临时存储和顺序跟踪器
-	tempResults := make([]Result, 0, len(urls))
-	nextIndex := 0
-
-	// 实时处理和显示结果
-	for result := range resultChan {
-		// 临时存储结果
-		tempResults = append(tempResults, result)
-
-		// 按完成顺序显示
-		fmt.Printf("%-5d %-12v %-8s %-45s %s\n",
-			result.Index,
-			result.Duration,
-			result.Status,
-			result.URL,
-			result.Status+" (收到结果)")
-
-		// 按请求顺序显示结果(当达到nextIndex时)
-		sort.Slice(tempResults, func(i, j int) bool {
-			return tempResults[i].Index < tempResults[j].Index
-		})
-
-		for len(tempResults) > 0 && tempResults[0].Index == nextIndex {
-			r := tempResults[0]
-			tempResults = tempResults[1:]
-			results[nextIndex] = r
-			nextIndex++
-
-			if r.Err != nil {
-				fmt.Printf("❌ [%d] 错误结果: %v\n", r.Index, r.Err)
-			} else {
-				fmt.Printf("✅ [%d] 有序结果: %s\n", r.Index, r.Response)
-			}
+	// 1. 创建有序的URL列表(带序号): 命令行参数与 -urls 合并; 两者都未给出时才使用演示列表
+	var specs []urlSpec
+	for _, u := range flag.Args() {
+		specs = append(specs, urlSpec{URL: u})
+	}
+	if *urlsPath != "" {
+		loaded, err := loadURLSpecs(*urlsPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "读取请求列表失败: %v\n", err)
+			return 2
+		}
+		specs = append(specs, loaded...)
+	}
+	if len(specs) == 0 && *urlsPath != "" {
+		fmt.Fprintf(os.Stderr, "请求列表 %s 中没有请求\n", *urlsPath)
+		return 2
+	}
+	if len(specs) == 0 {
+		for _, u := range demoURLs {
+			specs = append(specs, urlSpec{URL: u})
+		}
+	}
+	var hosts map[string]string
+	if *envName != "" {
+		table, err := loadEnvTable(*envFile)
+		if err == nil {
+			hosts, err = table.hosts(*envName)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "加载环境 %s 失败: %v\n", *envName, err)
+			return 2
 		}
 	}
+	urls := make([]string, len(specs))
+	for i := range specs {
+		resolved, err := resolveURL(specs[i].URL, hosts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		specs[i].URL = resolved
+		urls[i] = resolved
+	}
 
-	// 7. 确保所有结果都按顺序处理
-	<-aggregateDone
+	var executor *runner.HTTPExecutor
+	newTask := mockTask
+	if !*mock {
+		executor = &runner.HTTPExecutor{
+			Method:            *method,
+			Header:            http.Header{"User-Agent": {*ua}},
+			PropagateDeadline: *deadlineHeaders,
+			RequestIDHeader:   *requestIDHeader,
+			Encoding:          *encoding,
+			AcceptEncoding:    *acceptEncoding,
+		}
+		if *captureDir != "" {
+			executor.CaptureRate = *captureRate
+			executor.CaptureFailures = true
+			executor.MaxCapture = *captureMax
+		}
+		if *body != "" {
+			executor.Body = []byte(*body)
+		}
+		newTask = executor.Task
+	}
 
-	// 8. 打印最终汇总报告(按请求顺序)
-	fmt.Println("\n======================= 最终结果(按请求顺序) =======================")
-	fmt.Printf("%-5s %-12s %-8s %-45s %s\n", "序号", "耗时", "状态", "请求地址", "详情")
-	fmt.Println("----------------------------------------------------------------------")
+	tasks := make([]runner.Task[runner.HTTPResponse], len(specs))
+	for i, spec := range specs {
+		if executor != nil && (spec.Method != "" || spec.Header != nil) {
+			tasks[i] = executor.With(spec.Method, spec.Header).Task(spec.URL)
+			continue
+		}
+		tasks[i] = newTask(spec.URL)
+	}
 
-	successCount := 0
-	for i, r := range results {
-		if r.Err == nil {
-			successCount++
+	var tracer runner.Tracer
+	if *otelEnabled {
+		t, shutdown, err := setupOTel(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "初始化 OpenTelemetry 失败: %v\n", err)
+			return 1
 		}
+		defer shutdown(context.Background())
+		tracer = t
+	}
+
+	// 2. 配置运行器: 按完成顺序立即显示, 并按请求顺序输出有序结果
+	cfg := runner.Config{
+		Concurrency: *concurrency,
+		MaxPerHost:  *maxPerHost,
+		Jitter:      *jitterWindow,
+		Timeout:     *timeout,
+		Retry: runner.RetryPolicy{
+			MaxAttempts: *retries + 1,
+			BaseDelay:   *retryDelay,
+			MaxDelay:    *retryMaxDelay,
+			Jitter:      *retryJitter,
+		},
+		RateLimit:  limiter,
+		Throttle:   throttle,
+		Breaker:    breaker,
+		Resources:  resources,
+		Tracer:     tracer,
+		Logger:     logger,
+		AutoReduce: *autoReduce,
+		FailFast:   *failFast,
+		RaceFirst:  *raceFirst,
+		Strict:     *strictMode,
+		Deadline:   *deadline,
+		Drain:      *drain,
+	}
+
+	ctx, stop := withSignals(context.Background())
+	defer stop()
+	if *cacheCheck {
+		return exitCode(ctx, runCacheCheck(ctx, cfg, executor, urls, out))
+	}
+	if *tlsAuditMode {
+		return exitCode(ctx, runTLSAudit(ctx, cfg, urls, time.Duration(*tlsWarnDays)*24*time.Hour, out))
+	}
+	if *headerAuditMode {
+		return exitCode(ctx, runHeaderAudit(ctx, cfg, tasks, out))
+	}
+	if *linkCheckMode {
+		return exitCode(ctx, runLinkCheck(ctx, cfg, tasks, out))
+	}
+	if *canaryBase != "" {
+		plan := canaryPlan{baseline: *baselineBase, canary: *canaryBase, percent: *canaryPercent, requests: *canaryRequests}
+		return exitCode(ctx, runCanary(ctx, cfg, executor, specs, plan, out))
+	}
+
+	// 未启用进度条时写入 io.Discard, 回调中无需判空
+	bar := newProgressBar(io.Discard)
+	if *showBar {
+		bar = newProgressBar(os.Stderr)
+	}
 
-		fmt.Printf("%-5d %-12v %-8s %-45s ", i, r.Duration, r.Status, r.URL)
-		if r.Err != nil {
-			fmt.Printf("❌ %v\n", r.Err)
-		} else {
-			fmt.Printf("✅ %s\n", r.Response)
+	var sampler *bodySampler
+	if *captureDir != "" {
+		if sampler, err = newBodySampler(*captureDir); err != nil {
+			fmt.Fprintf(os.Stderr, "创建响应体采样目录失败: %v\n", err)
+			return 1
 		}
 	}
 
-	// 9. 统计信息
-	totalTime := time.Since(totalStart)
-	fmt.Println("\n======================= 执行统计 =======================")
-	fmt.Printf("总请求数: %d\n", len(urls))
-	fmt.Printf("成功请求: %d\n", successCount)
-	fmt.Printf("失败请求: %d\n", len(urls)-successCount)
-	fmt.Printf("成功率: %.1f%%\n", float64(successCount)/float64(len(urls))*100)
-	fmt.Printf("总执行时间: %v (%.1fms/请求)\n", totalTime,
-		float64(totalTime.Microseconds())/1000/float64(len(urls)))
+	var reporter runner.Reporter[runner.HTTPResponse]
+	var jsonOut *jsonReporter
+	switch *format {
+	case formatText:
+		reporter = &textReporter{out: out, opts: reportOptions{
+			encoding:        *encoding,
+			bodySize:        len(*body),
+			acceptEncoding:  *acceptEncoding,
+			requestIDHeader: *requestIDHeader,
+			jitter:          *jitterWindow,
+			rate:            *rate,
+			ratePer:         *ratePer,
+			rateAlgo:        *rateAlgo,
+			resources:       resources != nil,
+			maxPerHost:      *maxPerHost,
+			trim:            *trim,
+			raceFirst:       *raceFirst,
+		}}
+	case formatJSON, formatNDJSON:
+		jsonOut = newJSONReporter(out, *format == formatNDJSON)
+		reporter = jsonOut
+	default:
+		reporter = runner.SilentReporter[runner.HTTPResponse]{}
+	}
 
-	// 10. 显示最快和最慢请求
-	if len(results) > 0 {
-		sort.Slice(results, func(i, j int) bool {
-			return results[i].Duration < results[j].Duration
-		})
+	r := &runner.Runner[runner.HTTPResponse]{
+		Config:   cfg,
+		Reporter: barReporter{reporter, bar},
+		OnResult: func(result runner.Result[runner.HTTPResponse]) {
+			if sampler != nil {
+				sampler.save(result)
+			}
+			if !completedFilter.allow(result.Err) {
+				return
+			}
+			bar.clear()
+			fmt.Fprintf(progress, "%-5d %-12v %-8s %-45s %s\n",
+				result.Index,
+				result.Duration,
+				result.Status,
+				result.URL,
+				result.Status+" (收到结果)")
+		},
+		OnProgress: bar.render,
+		OnOrdered: func(result runner.Result[runner.HTTPResponse]) {
+			if !orderedFilter.allow(result.Err) {
+				return
+			}
+			bar.clear()
+			if result.Err != nil {
+				fmt.Fprintf(progress, "❌ [%d] 错误结果: %v\n", result.Index, result.Err)
+			} else {
+				fmt.Fprintf(progress, "✅ [%d] 有序结果: %s\n", result.Index, result.Value)
+			}
+		},
+	}
 
-		fastest := results[0]
-		slowest := results[len(results)-1]
+	// 3. 启动所有并发请求并等待有序结果
+	fmt.Fprintln(progress, "开始并发请求...")
+	fmt.Fprintf(progress, "%-5s %-12s %-8s %-45s %s\n", "序号", "耗时", "状态", "请求地址", "详情")
+	fmt.Fprintln(progress, "----------------------------------------------------------------------")
 
-		fmt.Println("\n======================= 性能分析 =======================")
-		fmt.Printf("最快请求: #%d %s (%v)\n", fastest.Index, fastest.URL, fastest.Duration)
-		fmt.Printf("最慢请求: #%d %s (%v)\n", slowest.Index, slowest.URL, slowest.Duration)
-		fmt.Printf("速度差距: %v (%.1f%%)\n",
-			slowest.Duration-fastest.Duration,
-			float64(slowest.Duration-fastest.Duration)/float64(fastest.Duration)*100)
+	results, runErr := r.RunWithContext(ctx, tasks)
+
+	if *csvPath != "" {
+		if err := writeCSVReport(*csvPath, results); err != nil {
+			fmt.Fprintf(os.Stderr, "写入 CSV 失败: %v\n", err)
+			return 1
+		}
+	}
+	if *badgePath != "" {
+		success := 0
+		for _, r := range results {
+			if r.Err == nil {
+				success++
+			}
+		}
+		if err := writeBadge(*badgePath, newBadge(success, len(results))); err != nil {
+			fmt.Fprintf(os.Stderr, "写入徽章失败: %v\n", err)
+			return 1
+		}
+	}
+
+	if sampler != nil {
+		if sampler.err != nil {
+			fmt.Fprintf(os.Stderr, "写入响应体样本失败: %v\n", sampler.err)
+			return 1
+		}
+		fmt.Fprintf(progress, "已保存 %d 个响应体样本到 %s\n", sampler.saved, *captureDir)
+	}
+	if jsonOut != nil && jsonOut.err != nil {
+		fmt.Fprintf(os.Stderr, "写入报告失败: %v\n", jsonOut.err)
+		return 1
+	}
+
+	if runErr != nil {
+		fmt.Fprintf(os.Stderr, "⛔ %v\n", runErr)
+		return 1
+	}
+	incomplete := 0
+	for _, r := range results {
+		if r.Status == runner.StatusIncomplete {
+			incomplete++
+		}
+	}
+	if incomplete > 0 {
+		fmt.Fprintf(os.Stderr, "⛔ %v, %d 个请求未完成\n", runner.ErrDeadline, incomplete)
+		return 1
+	}
+	if cause := context.Cause(ctx); errors.Is(cause, errInterrupted) {
+		fmt.Fprintf(os.Stderr, "⛔ %v, 以上为已完成部分的报告\n", cause)
+		return exitInterrupted
 	}
+	return 0
 }
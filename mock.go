@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// mockTask 构造一个模拟请求: 随机耗时 0~1s, 约 20% 概率失败
+func mockTask(url string) runner.Task[runner.HTTPResponse] {
+	return runner.Task[runner.HTTPResponse]{
+		URL: url,
+		Do: func(ctx context.Context) (runner.HTTPResponse, error) {
+			delay := time.Duration(rand.Intn(1000)) * time.Millisecond
+
+			// 使用定时器代替 time.Sleep, 以便取消/超时能立即打断模拟请求
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+
+			select {
+			case <-ctx.Done():
+				return runner.HTTPResponse{}, fmt.Errorf("请求取消 [%s]: %w", url, ctx.Err())
+			case <-timer.C:
+			}
+
+			if rand.Intn(10) < 2 {
+				return runner.HTTPResponse{StatusCode: http.StatusInternalServerError},
+					fmt.Errorf("请求失败 [%s] (耗时: %v)", url, delay)
+			}
+			return runner.HTTPResponse{StatusCode: http.StatusOK, Size: int64(rand.Intn(4096))}, nil
+		},
+	}
+}
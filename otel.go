@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// otelTracer 把 runner.Tracer 适配到 OpenTelemetry: 一次运行一个父 span, 每个任务一个子 span
+type otelTracer struct {
+	tracer trace.Tracer
+}
+
+func (t otelTracer) StartRun(ctx context.Context, tasks int) (context.Context, func(error)) {
+	ctx, span := t.tracer.Start(ctx, "go-routine.run", trace.WithAttributes(attribute.Int("tasks", tasks)))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+func (t otelTracer) StartTask(ctx context.Context, index int, url string) (context.Context, func(int, string, error)) {
+	ctx, span := t.tracer.Start(ctx, "go-routine.task", trace.WithAttributes(
+		attribute.Int("task.index", index),
+		semconv.URLFull(url),
+	))
+	return ctx, func(attempts int, status string, err error) {
+		span.SetAttributes(attribute.Int("task.attempts", attempts), attribute.String("task.status", status))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// setupOTel 创建经 OTLP/HTTP 导出的追踪器; 导出地址等由标准的 OTEL_EXPORTER_OTLP_* 环境变量配置。
+// 返回的 shutdown 在进程退出前调用, 确保缓冲中的 span 全部导出
+func setupOTel(ctx context.Context) (runner.Tracer, func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	res := resource.NewSchemaless(
+		semconv.ServiceName("go-routine"),
+		semconv.ServiceVersion(readBuildInfo().Version),
+	)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	return otelTracer{tracer: tp.Tracer("github.com/abnerCrack/go-routine")}, tp.Shutdown, nil
+}
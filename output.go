@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// openOutput 按名称打开输出目标: stdout、stderr、none(丢弃) 或文件路径
+func openOutput(dest string) (io.Writer, func() error, error) {
+	noop := func() error { return nil }
+	switch dest {
+	case "", "-", "stdout":
+		return os.Stdout, noop, nil
+	case "stderr":
+		return os.Stderr, noop, nil
+	case "none":
+		return io.Discard, noop, nil
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f.Close, nil
+}
+
+// printFilter 控制实时输出的密度: 只输出失败结果, 或每 N 条输出一条
+type printFilter struct {
+	every        int
+	onlyFailures bool
+	seen         int
+}
+
+func newPrintFilter(every int, only string) (*printFilter, error) {
+	f := &printFilter{every: every}
+	switch only {
+	case "", "all":
+	case "failures":
+		f.onlyFailures = true
+	default:
+		return nil, fmt.Errorf("未知的 -print-only 取值 %q (可选 all、failures)", only)
+	}
+	return f, nil
+}
+
+// allow 判断错误为 err 的结果是否需要输出
+func (f *printFilter) allow(err error) bool {
+	if f.onlyFailures && err == nil {
+		return false
+	}
+	f.seen++
+	return f.every <= 1 || (f.seen-1)%f.every == 0
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// progressBar 在终端的同一行上重绘进度条; 只用回车覆盖, 不依赖 ANSI 控制序列,
+// 在 Windows 控制台上同样可用
+type progressBar struct {
+	w       io.Writer
+	width   int // 进度条格数
+	lastLen int // 上一次绘制占用的列数, 用于清除
+}
+
+func newProgressBar(w io.Writer) *progressBar {
+	return &progressBar{w: w, width: 30}
+}
+
+// render 绘制一次进度快照
+func (b *progressBar) render(p runner.Progress) {
+	filled := b.width
+	if p.Total > 0 {
+		filled = b.width * p.Completed / p.Total
+	}
+	line := fmt.Sprintf("[%s%s] %d/%d %3.0f%% 运行中 %d 出错 %d 已用 %v 剩余 %v",
+		strings.Repeat("#", filled), strings.Repeat("-", b.width-filled),
+		p.Completed, p.Total, p.Percent(), p.Running, p.Errors,
+		p.Elapsed.Round(100*time.Millisecond), p.ETA.Round(100*time.Millisecond))
+	b.clear()
+	fmt.Fprint(b.w, line)
+	b.lastLen = displayWidth(line)
+}
+
+// displayWidth 估算字符串在终端上占用的列数: 中日韩等宽字符按 2 列计
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x1100 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// clear 擦除当前进度条, 以便其他输出不与之混在一行
+func (b *progressBar) clear() {
+	if b.lastLen == 0 {
+		return
+	}
+	fmt.Fprint(b.w, "\r"+strings.Repeat(" ", b.lastLen)+"\r")
+	b.lastLen = 0
+}
+
+// finish 保留最后一次绘制的进度条并换行
+func (b *progressBar) finish() {
+	if b.lastLen > 0 {
+		fmt.Fprintln(b.w)
+		b.lastLen = 0
+	}
+}
+
+// barReporter 在最终报告输出前收起进度条, 避免报告接在进度条的同一行
+type barReporter struct {
+	runner.Reporter[runner.HTTPResponse]
+	bar *progressBar
+}
+
+func (b barReporter) OnFinish(s runner.Summary[runner.HTTPResponse]) {
+	b.bar.finish()
+	b.Reporter.OnFinish(s)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"strconv"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// writeCSVReport 把按提交顺序排列的结果写入 CSV 文件, 列与 JSON 报告的字段同名
+func writeCSVReport(path string, results []runner.Result[runner.HTTPResponse]) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"index", "url", "status", "duration_ms", "attempts", "error"})
+	for _, r := range results {
+		var errText string
+		if r.Err != nil {
+			errText = r.Err.Error()
+		}
+		w.Write([]string{
+			strconv.Itoa(r.Index),
+			r.URL,
+			r.Status,
+			strconv.FormatFloat(ms(r.Duration), 'f', 3, 64),
+			strconv.Itoa(r.Attempts),
+			errText,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// 报告格式
+const (
+	formatText   = "text"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatNone   = "none"
+)
+
+// jsonReporter 输出 -output json/ndjson 报告: ndjson 每收到一个结果写一行, json 在结束时写完整报告对象。
+// 写入失败时记录首个错误, 由调用方在运行结束后检查
+type jsonReporter struct {
+	enc    *json.Encoder
+	ndjson bool
+	err    error
+}
+
+func newJSONReporter(w io.Writer, ndjson bool) *jsonReporter {
+	enc := json.NewEncoder(w)
+	if !ndjson {
+		enc.SetIndent("", "  ")
+	}
+	return &jsonReporter{enc: enc, ndjson: ndjson}
+}
+
+func (j *jsonReporter) OnStart(int) {}
+
+func (j *jsonReporter) OnResult(r runner.Result[runner.HTTPResponse]) {
+	if j.ndjson && j.err == nil {
+		j.err = j.enc.Encode(newJSONResult(r))
+	}
+}
+
+func (j *jsonReporter) OnFinish(s runner.Summary[runner.HTTPResponse]) {
+	if !j.ndjson && j.err == nil {
+		j.err = j.enc.Encode(newJSONReport(s))
+	}
+}
+
+// jsonResult 是单个结果的 JSON 表示, 耗时统一为毫秒
+type jsonResult struct {
+	Index      int     `json:"index"`
+	URL        string  `json:"url"`
+	Status     string  `json:"status"`
+	DurationMS float64 `json:"duration_ms"`
+	JitterMS   float64 `json:"jitter_ms,omitempty"`
+	Attempts   int     `json:"attempts"`
+	StatusCode int     `json:"status_code,omitempty"`
+	Size       int64   `json:"size,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+func newJSONResult(r runner.Result[runner.HTTPResponse]) jsonResult {
+	jr := jsonResult{
+		Index:      r.Index,
+		URL:        r.URL,
+		Status:     r.Status,
+		DurationMS: ms(r.Duration),
+		JitterMS:   ms(r.Jitter),
+		Attempts:   r.Attempts,
+		StatusCode: r.Value.StatusCode,
+		Size:       r.Value.Size,
+	}
+	if r.Err != nil {
+		jr.Error = r.Err.Error()
+	}
+	return jr
+}
+
+// jsonReport 是 -output json 输出的完整报告
+type jsonReport struct {
+	Results []jsonResult `json:"results"`
+	Stats   jsonStats    `json:"stats"`
+}
+
+type jsonStats struct {
+	Total       int                           `json:"total"`
+	Success     int                           `json:"success"`
+	Failed      int                           `json:"failed"`
+	Statuses    map[string]int                `json:"statuses"`
+	TotalTimeMS float64                       `json:"total_time_ms"`
+	Workers     int                           `json:"workers"`
+	LaunchRate  float64                       `json:"launch_rate"`
+	SendBlocked int64                         `json:"send_blocked"`
+	Latency     jsonLatency                   `json:"latency"`
+	Hosts       map[string]runner.HostMetrics `json:"hosts,omitempty"`
+	Winner      *int                          `json:"winner,omitempty"`
+	Error       string                        `json:"error,omitempty"`
+}
+
+// jsonLatency 是 runner.Stats 的 JSON 表示, 单位毫秒
+type jsonLatency struct {
+	Count  int     `json:"count"`
+	MinMS  float64 `json:"min_ms"`
+	MaxMS  float64 `json:"max_ms"`
+	MeanMS float64 `json:"mean_ms"`
+	StdDev float64 `json:"stddev_ms"`
+	P50MS  float64 `json:"p50_ms"`
+	P90MS  float64 `json:"p90_ms"`
+	P95MS  float64 `json:"p95_ms"`
+	P99MS  float64 `json:"p99_ms"`
+}
+
+func newJSONLatency(s runner.Stats) jsonLatency {
+	return jsonLatency{
+		Count:  s.Count,
+		MinMS:  ms(s.Min),
+		MaxMS:  ms(s.Max),
+		MeanMS: ms(s.Mean),
+		StdDev: ms(s.StdDev),
+		P50MS:  ms(s.P50),
+		P90MS:  ms(s.P90),
+		P95MS:  ms(s.P95),
+		P99MS:  ms(s.P99),
+	}
+}
+
+// newJSONReport 把运行汇总转换为完整报告对象
+func newJSONReport(s runner.Summary[runner.HTTPResponse]) jsonReport {
+	report := jsonReport{
+		Results: make([]jsonResult, len(s.Results)),
+		Stats: jsonStats{
+			Total:       len(s.Results),
+			Statuses:    make(map[string]int),
+			TotalTimeMS: ms(s.Elapsed),
+			Workers:     s.Metrics.Workers,
+			LaunchRate:  s.Metrics.LaunchRate,
+			SendBlocked: s.Metrics.SendBlocked,
+			Latency:     newJSONLatency(s.Stats),
+			Hosts:       s.Metrics.Hosts,
+		},
+	}
+	for i, r := range s.Results {
+		report.Results[i] = newJSONResult(r)
+		report.Stats.Statuses[r.Status]++
+		if r.Err == nil {
+			report.Stats.Success++
+		}
+	}
+	report.Stats.Failed = report.Stats.Total - report.Stats.Success
+	if s.Metrics.Winner >= 0 {
+		report.Stats.Winner = &s.Metrics.Winner
+	}
+	if s.Err != nil {
+		report.Stats.Error = s.Err.Error()
+	}
+	return report
+}
+
+// ms 把时长换算为毫秒
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// reportOptions 是文本报告中与命令行参数相关的部分, 用于决定打印哪些统计项
+type reportOptions struct {
+	encoding        string
+	bodySize        int
+	acceptEncoding  string
+	requestIDHeader string
+	jitter          time.Duration
+	rate            int
+	ratePer         time.Duration
+	rateAlgo        string
+	resources       bool
+	maxPerHost      int
+	trim            float64
+	raceFirst       bool
+}
+
+// textReporter 在运行结束时打印 -output text 的最终报告: 结果表、执行统计与性能分析
+type textReporter struct {
+	runner.SilentReporter[runner.HTTPResponse]
+	out  io.Writer
+	opts reportOptions
+}
+
+func (t *textReporter) OnFinish(s runner.Summary[runner.HTTPResponse]) {
+	results := s.Results
+
+	// 1. 最终结果(按请求顺序)
+	fmt.Fprintln(t.out, "\n======================= 最终结果(按请求顺序) =======================")
+	fmt.Fprintf(t.out, "%-5s %-12s %-8s %-4s %-45s %s\n", "序号", "耗时", "状态", "次数", "请求地址", "详情")
+	fmt.Fprintln(t.out, "----------------------------------------------------------------------")
+
+	successCount := 0
+	retriedCount, retryTotal := 0, 0
+	echoMismatch := 0
+	encodings := make(map[string]int)
+	statusCounts := make(map[string]int)
+	for i, r := range results {
+		if r.Err == nil {
+			successCount++
+		}
+		statusCounts[r.Status]++
+		if r.Attempts > 1 {
+			retriedCount++
+			retryTotal += r.Attempts - 1
+		}
+		if r.Value.EchoMismatch() {
+			echoMismatch++
+		}
+		if r.Value.StatusCode != 0 {
+			switch {
+			case r.Value.Decompressed:
+				encodings["gzip(已透明解压)"]++
+			case r.Value.ContentEncoding != "":
+				encodings[r.Value.ContentEncoding]++
+			default:
+				encodings["identity"]++
+			}
+		}
+
+		fmt.Fprintf(t.out, "%-5d %-12v %-8s %-4d %-45s ", i, r.Duration, r.Status, r.Attempts, r.URL)
+		if r.Err != nil {
+			fmt.Fprintf(t.out, "❌ %v\n", r.Err)
+		} else {
+			fmt.Fprintf(t.out, "✅ %s\n", r.Value)
+		}
+	}
+
+	// 2. 统计信息
+	totalTime := s.Elapsed
+	fmt.Fprintln(t.out, "\n======================= 执行统计 =======================")
+	fmt.Fprintf(t.out, "总请求数: %d\n", len(results))
+	fmt.Fprintf(t.out, "成功请求: %d\n", successCount)
+	fmt.Fprintf(t.out, "失败请求: %d\n", len(results)-successCount)
+	fmt.Fprintf(t.out, "状态分布:")
+	for _, status := range []string{runner.StatusSuccess, runner.StatusFailed, runner.StatusTimeout, runner.StatusThrottled, runner.StatusShortCircuited, runner.StatusExhausted, runner.StatusIncomplete, runner.StatusCanceled} {
+		if statusCounts[status] > 0 {
+			fmt.Fprintf(t.out, " %s %d", status, statusCounts[status])
+		}
+	}
+	fmt.Fprintln(t.out)
+	if retriedCount > 0 {
+		fmt.Fprintf(t.out, "重试: %d 个请求共重试 %d 次\n", retriedCount, retryTotal)
+	}
+	if t.opts.encoding != "" && t.opts.bodySize > 0 {
+		encoded := 0
+		for _, r := range results {
+			if r.Value.EncodedBodySize > 0 {
+				encoded = r.Value.EncodedBodySize
+				break
+			}
+		}
+		fmt.Fprintf(t.out, "请求体压缩(%s): %d 字节 → %d 字节 (%.1f%%)\n",
+			t.opts.encoding, t.opts.bodySize, encoded, float64(encoded)*100/float64(t.opts.bodySize))
+	}
+	if t.opts.encoding != "" || t.opts.acceptEncoding != "" {
+		names := make([]string, 0, len(encodings))
+		for name := range encodings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprint(t.out, "响应编码:")
+		for _, name := range names {
+			fmt.Fprintf(t.out, " %s %d", name, encodings[name])
+		}
+		fmt.Fprintln(t.out)
+	}
+	if t.opts.requestIDHeader != "" {
+		fmt.Fprintf(t.out, "请求 ID 回显: %d 个响应未原样回显 %s", echoMismatch, t.opts.requestIDHeader)
+		if echoMismatch > 0 {
+			fmt.Fprint(t.out, " (可能来自缓存或中间层)")
+		}
+		fmt.Fprintln(t.out)
+	}
+	if stats := s.Stats; stats.Count > 0 {
+		fmt.Fprintf(t.out, "耗时分位: p50 %v, p90 %v, p95 %v, p99 %v\n", stats.P50, stats.P90, stats.P95, stats.P99)
+		fmt.Fprintf(t.out, "耗时均值: %v (标准差 %v)\n", stats.Mean, stats.StdDev)
+	}
+	fmt.Fprintf(t.out, "成功率: %.1f%%\n", float64(successCount)/float64(len(results))*100)
+	fmt.Fprintf(t.out, "总执行时间: %v (%.1fms/请求)\n", totalTime,
+		float64(totalTime.Microseconds())/1000/float64(len(results)))
+	if t.opts.jitter > 0 {
+		var totalJitter, maxJitter time.Duration
+		for _, r := range results {
+			totalJitter += r.Jitter
+			maxJitter = max(maxJitter, r.Jitter)
+		}
+		fmt.Fprintf(t.out, "启动抖动: 窗口 %v, 平均 %v, 最大 %v\n",
+			t.opts.jitter, totalJitter/time.Duration(len(results)), maxJitter)
+	}
+	fmt.Fprintf(t.out, "工作协程数: %d\n", s.Metrics.Workers)
+	if s.Metrics.FinalWorkers < s.Metrics.Workers {
+		fmt.Fprintf(t.out, "⚠️ 因本机资源耗尽自动降并发: %d -> %d\n", s.Metrics.Workers, s.Metrics.FinalWorkers)
+	}
+	if statusCounts[runner.StatusExhausted] > 0 {
+		fmt.Fprintf(t.out, "⚠️ %d 个请求因本机文件描述符/临时端口耗尽失败, 建议: %s\n",
+			statusCounts[runner.StatusExhausted], runner.ExhaustionHint)
+	}
+	if t.opts.rate > 0 {
+		fmt.Fprintf(t.out, "限流: %d/%v (%s), 实际启动速率 %.1f 个/秒\n", t.opts.rate, t.opts.ratePer, t.opts.rateAlgo, s.Metrics.LaunchRate)
+	} else {
+		fmt.Fprintf(t.out, "启动速率: %.1f 个/秒\n", s.Metrics.LaunchRate)
+	}
+	fmt.Fprintf(t.out, "结果通道容量: %d\n", s.Metrics.ChannelCap)
+	fmt.Fprintf(t.out, "生产者阻塞: %d 次 (累计 %v)\n", s.Metrics.SendBlocked, s.Metrics.SendBlockedTime)
+	if s.Metrics.SendBlocked > 0 {
+		fmt.Fprintln(t.out, "⚠️ 生产者曾在结果通道上阻塞, 并发请求可能已被串行化")
+	}
+	if t.opts.resources {
+		fmt.Fprintf(t.out, "资源峰值: %v\n", s.Metrics.ResourcePeak)
+		if s.Metrics.ResourceAdjusted != "" {
+			fmt.Fprintf(t.out, "⚠️ %s\n", s.Metrics.ResourceAdjusted)
+		}
+		if s.Metrics.ResourceWaits > 0 {
+			fmt.Fprintf(t.out, "⚠️ 资源保护暂停分发 %d 次 (累计 %v), 最近原因: %s\n",
+				s.Metrics.ResourceWaits, s.Metrics.ResourceWaitTime, s.Metrics.ResourceReason)
+		}
+	}
+	if t.opts.maxPerHost > 0 || len(s.Metrics.Hosts) > 1 {
+		hosts := make([]string, 0, len(s.Metrics.Hosts))
+		for host := range s.Metrics.Hosts {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+		fmt.Fprintln(t.out, "按主机统计:")
+		for _, host := range hosts {
+			h := s.Metrics.Hosts[host]
+			fmt.Fprintf(t.out, "  %-30s 请求 %d, 最大并发 %d, 被推迟 %d\n", host, h.Tasks, h.Peak, h.Deferred)
+		}
+	}
+
+	if t.opts.raceFirst {
+		t.printRace(s)
+	}
+
+	// 3. 显示最快和最慢请求, 与耗时统计使用同一批样本(未发出或被本地拒绝的请求不计入)
+	var measured []runner.Result[runner.HTTPResponse]
+	for _, r := range results {
+		if r.Measured() {
+			measured = append(measured, r)
+		}
+	}
+	if len(measured) > 0 {
+		sort.Slice(measured, func(i, j int) bool {
+			return measured[i].Duration < measured[j].Duration
+		})
+
+		fastest := measured[0]
+		slowest := measured[len(measured)-1]
+
+		fmt.Fprintln(t.out, "\n======================= 性能分析 =======================")
+		fmt.Fprintf(t.out, "最快请求: #%d %s (%v)\n", fastest.Index, fastest.URL, fastest.Duration)
+		fmt.Fprintf(t.out, "最慢请求: #%d %s (%v)\n", slowest.Index, slowest.URL, slowest.Duration)
+		if fastest.Duration > 0 {
+			fmt.Fprintf(t.out, "速度差距: %v (%.1f%%)\n",
+				slowest.Duration-fastest.Duration,
+				float64(slowest.Duration-fastest.Duration)/float64(fastest.Duration)*100)
+		} else {
+			fmt.Fprintf(t.out, "速度差距: %v\n", slowest.Duration-fastest.Duration)
+		}
+
+		// 4. 离群请求检测(IQR)与截尾统计; 均值见执行统计中的耗时均值
+		durations := make([]time.Duration, len(measured))
+		for i, r := range measured {
+			durations[i] = r.Duration
+		}
+		lo, hi := iqrBounds(durations)
+
+		var outliers []runner.Result[runner.HTTPResponse]
+		for _, r := range measured {
+			if r.Duration < lo || r.Duration > hi {
+				outliers = append(outliers, r)
+			}
+		}
+
+		if t.opts.trim > 0 {
+			fmt.Fprintf(t.out, "截尾平均(两端各 %.0f%%): %v\n", t.opts.trim*100, trimmedMean(durations, t.opts.trim))
+		}
+		fmt.Fprintf(t.out, "正常耗时范围(IQR): [%v, %v]\n", max(lo, 0), hi)
+		fmt.Fprintf(t.out, "离群请求: %d 个\n", len(outliers))
+		for _, r := range outliers {
+			fmt.Fprintf(t.out, "  #%d %s (%v)\n", r.Index, r.URL, r.Duration)
+		}
+	}
+}
+
+// printRace 打印竞速结果: 胜者, 以及落败请求在被取消前运行了多久
+func (t *textReporter) printRace(s runner.Summary[runner.HTTPResponse]) {
+	fmt.Fprintln(t.out, "\n======================= 竞速结果 =======================")
+	if s.Metrics.Winner < 0 {
+		fmt.Fprintln(t.out, "没有请求成功")
+		return
+	}
+	w := s.Results[s.Metrics.Winner]
+	fmt.Fprintf(t.out, "胜者: #%d %s (%v)\n", w.Index, w.URL, w.Duration)
+	for _, r := range s.Results {
+		switch {
+		case r.Index == w.Index:
+		case r.Attempts == 0:
+			fmt.Fprintf(t.out, "  #%d %-45s 未启动\n", r.Index, r.URL)
+		case r.Status == runner.StatusCanceled:
+			fmt.Fprintf(t.out, "  #%d %-45s 运行 %v 后取消\n", r.Index, r.URL, r.Duration)
+		default:
+			fmt.Fprintf(t.out, "  #%d %-45s 取消前已结束: %s (%v)\n", r.Index, r.URL, r.Status, r.Duration)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrActorClosed 表示向已关闭的 Actor 投递消息
+var ErrActorClosed = errors.New("actor 已关闭")
+
+// Actor 在单个协程中按投递顺序逐个执行任务(串行邮箱), 适合按实体有序处理。
+// 结果与 Runner 一致: Index 为投递序号, ctx 取消后未执行的任务记为 StatusCanceled
+type Actor[T any] struct {
+	ctx     context.Context
+	cfg     Config
+	mailbox chan envelope[T]
+	done    chan struct{}
+
+	mu     sync.Mutex // 保证投递序号与邮箱顺序一致, 并与 Close 互斥
+	closed bool
+	seq    int
+
+	processed atomic.Int64
+	failed    atomic.Int64
+	canceled  atomic.Int64
+}
+
+// ActorMetrics 是 Actor 的运行指标
+type ActorMetrics struct {
+	Processed int64 // 已处理的消息数(含失败和取消)
+	Failed    int64 // 出错的消息数, 含被断路或本地限流拒绝的
+	Canceled  int64
+	Mailbox   int // 邮箱中等待处理的消息数
+}
+
+type envelope[T any] struct {
+	index  int
+	task   Task[T]
+	future *Future[T] // Tell 投递时为 nil
+}
+
+// Future 是 Ask 的异步结果
+type Future[T any] struct {
+	done   chan struct{}
+	result Result[T]
+}
+
+// Wait 等待结果就绪, ctx 先取消时返回 ctx 的错误
+func (f *Future[T]) Wait(ctx context.Context) (Result[T], error) {
+	select {
+	case <-f.done:
+		return f.result, nil
+	case <-ctx.Done():
+		return Result[T]{}, ctx.Err()
+	}
+}
+
+// NewActor 创建并启动一个邮箱容量为 mailbox 的 Actor。opts 中作用于单个任务的选项
+// (超时、重试、抖动、断路器、自适应限流、日志)对每条消息生效, 并发、速率等批量选项被忽略。
+// ctx 取消后 Actor 自行关闭: 不再接收消息, 邮箱中剩余的消息记为取消, 随后退出
+func NewActor[T any](ctx context.Context, mailbox int, opts ...Option) *Actor[T] {
+	a := &Actor[T]{
+		ctx:     ctx,
+		mailbox: make(chan envelope[T], mailbox),
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&a.cfg)
+	}
+	go a.loop()
+	return a
+}
+
+// Tell 投递任务且不等待结果, 邮箱已满时阻塞直到有空位或 ctx 取消
+func (a *Actor[T]) Tell(task Task[T]) error {
+	return a.post(task, nil)
+}
+
+// Ask 投递任务并返回可等待结果的 Future, 邮箱已满时的行为同 Tell
+func (a *Actor[T]) Ask(task Task[T]) (*Future[T], error) {
+	f := &Future[T]{done: make(chan struct{})}
+	if err := a.post(task, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (a *Actor[T]) post(task Task[T], f *Future[T]) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return ErrActorClosed
+	}
+
+	select {
+	case a.mailbox <- envelope[T]{index: a.seq, task: task, future: f}:
+		a.seq++
+		return nil
+	case <-a.ctx.Done():
+		return ErrActorClosed
+	}
+}
+
+// Close 停止接收新消息; 邮箱中已有的消息仍会处理(ctx 已取消时记为取消)
+func (a *Actor[T]) Close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.closed {
+		a.closed = true
+		close(a.mailbox)
+	}
+}
+
+// Done 在 Actor 处理完所有消息并退出后关闭
+func (a *Actor[T]) Done() <-chan struct{} {
+	return a.done
+}
+
+// Metrics 返回 Actor 当前的运行指标
+func (a *Actor[T]) Metrics() ActorMetrics {
+	return ActorMetrics{
+		Processed: a.processed.Load(),
+		Failed:    a.failed.Load(),
+		Canceled:  a.canceled.Load(),
+		Mailbox:   len(a.mailbox),
+	}
+}
+
+func (a *Actor[T]) loop() {
+	defer close(a.done)
+	for {
+		select {
+		case env, ok := <-a.mailbox:
+			if !ok {
+				return
+			}
+			a.handle(env)
+		case <-a.ctx.Done():
+			// post 在 ctx 取消时不再阻塞, Close 可以拿到锁; 关闭后剩余消息逐个记为取消
+			a.Close()
+			for env := range a.mailbox {
+				a.handle(env)
+			}
+			return
+		}
+	}
+}
+
+// handle 执行一条消息并更新指标, ctx 已取消时直接记为取消
+func (a *Actor[T]) handle(env envelope[T]) {
+	var result Result[T]
+	if err := a.ctx.Err(); err != nil {
+		result = Result[T]{Index: env.index, URL: env.task.URL, Status: StatusCanceled, Err: err}
+	} else {
+		result = execute(a.ctx, &a.cfg, env.index, env.task)
+	}
+
+	a.processed.Add(1)
+	switch {
+	case isError(result.Status):
+		a.failed.Add(1)
+	case result.Status == StatusCanceled:
+		a.canceled.Add(1)
+	}
+
+	if env.future != nil {
+		env.future.result = result
+		close(env.future.done)
+	}
+}
@@ -0,0 +1,174 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitDone 等待 Actor 退出, 超时则判定测试失败
+func waitDone[T any](t *testing.T, a *Actor[T]) {
+	t.Helper()
+	select {
+	case <-a.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Actor 未退出")
+	}
+}
+
+// 消息按投递顺序串行执行, 选项(超时、重试、断路器)生效, 出错计数包含被断路的消息
+func TestActorOptionsAndCounters(t *testing.T) {
+	a := NewActor[int](context.Background(), 8,
+		WithTimeout(50*time.Millisecond),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+		WithCircuitBreaker(2, time.Hour),
+	)
+
+	var mu sync.Mutex // 超时的任务可能在 execute 返回后才写入
+	var order []int
+	record := func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, i)
+	}
+	flaky := 0
+	tasks := []Task[int]{
+		{URL: "http://ok/", Do: func(ctx context.Context) (int, error) {
+			record(0)
+			return 1, nil
+		}},
+		{URL: "http://flaky/", Do: func(ctx context.Context) (int, error) {
+			record(1)
+			if flaky++; flaky == 1 {
+				return 0, errors.New("首次失败")
+			}
+			return 2, nil
+		}},
+		{URL: "http://slow/", Do: func(ctx context.Context) (int, error) {
+			record(2)
+			return blockUntilDone(ctx)
+		}},
+		{URL: "http://slow/", Do: func(ctx context.Context) (int, error) {
+			t.Error("断路后的消息不应执行")
+			return 0, nil
+		}},
+	}
+	futures := make([]*Future[int], len(tasks))
+	for i, task := range tasks {
+		f, err := a.Ask(task)
+		if err != nil {
+			t.Fatal(err)
+		}
+		futures[i] = f
+	}
+	a.Close()
+	waitDone(t, a)
+
+	want := []struct {
+		status   string
+		attempts int
+	}{
+		{StatusSuccess, 1},
+		{StatusSuccess, 2},
+		{StatusTimeout, 2},
+		{StatusShortCircuited, 0},
+	}
+	for i, f := range futures {
+		res, err := f.Wait(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Index != i || res.Status != want[i].status || res.Attempts != want[i].attempts {
+			t.Errorf("#%d: Index %d 状态 %s 次数 %d, 期望状态 %s 次数 %d",
+				i, res.Index, res.Status, res.Attempts, want[i].status, want[i].attempts)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []int{0, 1, 1, 2, 2}; !slices.Equal(order, want) {
+		t.Errorf("执行顺序 %v, 期望 %v", order, want)
+	}
+	if m := a.Metrics(); m.Processed != 4 || m.Failed != 2 || m.Canceled != 0 || m.Mailbox != 0 {
+		t.Errorf("指标 %+v, 期望处理 4、出错 2、取消 0", m)
+	}
+}
+
+// ctx 取消后 Actor 无需 Close 即自行退出: 运行中与排队的消息记为取消, 阻塞的投递返回
+func TestActorContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := NewActor[int](ctx, 1)
+
+	started := make(chan struct{})
+	running, err := a.Ask(Task[int]{URL: "http://a/", Do: func(ctx context.Context) (int, error) {
+		close(started)
+		return blockUntilDone(ctx)
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	queued, err := a.Ask(Task[int]{URL: "http://a/", Do: sleepThen(0, nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 邮箱已满, 这次投递阻塞到 ctx 取消
+	blocked := make(chan error, 1)
+	go func() {
+		blocked <- a.Tell(Task[int]{URL: "http://a/", Do: sleepThen(0, nil)})
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	waitDone(t, a)
+
+	select {
+	case <-blocked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("投递在 ctx 取消后仍然阻塞")
+	}
+	for i, f := range []*Future[int]{running, queued} {
+		res, _ := f.Wait(context.Background())
+		if res.Status != StatusCanceled || !errors.Is(res.Err, context.Canceled) {
+			t.Errorf("#%d 状态 %s, 错误 %v, 期望取消", i, res.Status, res.Err)
+		}
+	}
+	if err := a.Tell(Task[int]{URL: "http://a/", Do: sleepThen(0, nil)}); !errors.Is(err, ErrActorClosed) {
+		t.Errorf("取消后投递返回 %v, 期望 ErrActorClosed", err)
+	}
+	if m := a.Metrics(); m.Processed != m.Canceled || m.Canceled < 2 || m.Failed != 0 {
+		t.Errorf("指标 %+v, 期望全部记为取消", m)
+	}
+}
+
+// Close 之后拒绝新消息, 已在邮箱中的消息仍会处理
+func TestActorClose(t *testing.T) {
+	a := NewActor[int](context.Background(), 4)
+	var futures []*Future[int]
+	for i := 0; i < 3; i++ {
+		f, err := a.Ask(Task[int]{URL: "http://a/", Do: sleepThen(5*time.Millisecond, nil)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		futures = append(futures, f)
+	}
+	a.Close()
+	a.Close() // 重复关闭无副作用
+
+	if err := a.Tell(Task[int]{URL: "http://a/", Do: sleepThen(0, nil)}); !errors.Is(err, ErrActorClosed) {
+		t.Errorf("关闭后投递返回 %v, 期望 ErrActorClosed", err)
+	}
+	waitDone(t, a)
+	for i, f := range futures {
+		if res, _ := f.Wait(context.Background()); res.Status != StatusSuccess {
+			t.Errorf("#%d 状态 %s, 期望关闭前投递的消息照常完成", i, res.Status)
+		}
+	}
+	if m := a.Metrics(); m.Processed != 3 || m.Failed != 0 || m.Canceled != 0 {
+		t.Errorf("指标 %+v", m)
+	}
+}
@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 表示目标主机的断路器处于打开状态, 请求未发出
+var ErrCircuitOpen = errors.New("断路器打开")
+
+// CircuitBreaker 按主机实现断路器: 同一主机连续 Threshold 次出错后打开,
+// 冷却 Cooldown 期间该主机的请求直接短路; 冷却结束后放行一个探测请求(半开),
+// 探测得到应答则关闭, 出错则重新打开。只有服务端未应答(超时、连接错误等)或返回过载状态码(429、503)
+// 才算出错, 服务端正常作答的 4xx 说明主机可用, 不计入
+type CircuitBreaker struct {
+	Threshold int           // 打开断路器所需的连续出错次数
+	Cooldown  time.Duration // 打开后的冷却时间
+
+	mu    sync.Mutex
+	hosts map[string]*breakerState
+}
+
+// NewCircuitBreaker 创建连续 threshold 次出错后打开、冷却 cooldown 的断路器
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{Threshold: threshold, Cooldown: cooldown}
+}
+
+type breakerState struct {
+	failures  int       // 连续出错次数
+	openUntil time.Time // 非零时断路器已打开, 到期后进入半开
+	probing   bool      // 半开状态下是否已有探测请求在途
+}
+
+func (b *CircuitBreaker) state(host string) *breakerState {
+	if b.hosts == nil {
+		b.hosts = make(map[string]*breakerState)
+	}
+	s, ok := b.hosts[host]
+	if !ok {
+		s = &breakerState{}
+		b.hosts[host] = s
+	}
+	return s
+}
+
+// allow 判断能否向 host 发出请求; 半开状态下只放行一个探测请求, probe 报告本次是否为该探测
+func (b *CircuitBreaker) allow(host string, now time.Time) (ok, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := b.state(host)
+	switch {
+	case s.openUntil.IsZero():
+		return true, false
+	case now.Before(s.openUntil), s.probing:
+		return false, false
+	default:
+		s.probing = true
+		return true, true
+	}
+}
+
+// record 记录一次经 allow 放行的请求的结果; probe 为 allow 返回的同名值。
+// 断路器打开或半开期间, 只有探测请求的结果能改变状态, 打开前已在途的请求结果被忽略
+func (b *CircuitBreaker) record(host, status string, err error, probe bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := b.state(host)
+	if probe {
+		s.probing = false
+	} else if !s.openUntil.IsZero() {
+		return
+	}
+	switch {
+	case serverAccepted(status, err):
+		s.failures, s.openUntil = 0, time.Time{}
+	case failed(status):
+		s.failures++
+		if probe || s.failures >= max(b.Threshold, 1) {
+			s.openUntil = now.Add(b.Cooldown)
+		}
+	}
+	// 取消、本地限流等其他状态不影响计数, 探测请求借此让出名额
+}
@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// failingTasks 返回 n 个同一主机、以 err 失败的任务
+func failingTasks(n int, err error) []Task[int] {
+	tasks := make([]Task[int], n)
+	for i := range tasks {
+		tasks[i] = Task[int]{URL: "http://down.example/", Do: func(ctx context.Context) (int, error) {
+			return 0, err
+		}}
+	}
+	return tasks
+}
+
+// 被短路的尝试未发出, 不计入 Attempts, 也不进入耗时统计
+func TestBreakerShortCircuitAttempts(t *testing.T) {
+	r := &Runner[int]{Config: Config{Concurrency: 1, Breaker: NewCircuitBreaker(2, time.Hour)}}
+	results := r.Run(failingTasks(20, errors.New("connection refused")))
+
+	sent := 0
+	for i, res := range results {
+		switch res.Status {
+		case StatusFailed:
+			sent += res.Attempts
+		case StatusShortCircuited:
+			if res.Attempts != 0 || !errors.Is(res.Err, ErrCircuitOpen) {
+				t.Errorf("#%d 被短路, Attempts = %d, 错误 %v", i, res.Attempts, res.Err)
+			}
+		default:
+			t.Errorf("#%d 状态 %s", i, res.Status)
+		}
+	}
+	if sent != 2 {
+		t.Errorf("发出 %d 次请求, 期望 2 次", sent)
+	}
+	if stats := ComputeStats(results); stats.Count != 2 {
+		t.Errorf("Stats.Count = %d, 期望只统计发出的 2 个请求", stats.Count)
+	}
+}
+
+// 服务端正常作答的 4xx 说明主机可用, 不应打开断路器
+func TestBreakerIgnoresAnswered4xx(t *testing.T) {
+	r := &Runner[int]{Config: Config{Concurrency: 1, Breaker: NewCircuitBreaker(2, time.Hour)}}
+	results := r.Run(failingTasks(5, &StatusError{Code: 404}))
+	for i, res := range results {
+		if res.Status != StatusFailed {
+			t.Errorf("#%d 状态 %s, 期望失败而非断路", i, res.Status)
+		}
+	}
+
+	results = r.Run(failingTasks(3, &StatusError{Code: 503}))
+	if results[2].Status != StatusShortCircuited {
+		t.Errorf("连续 503 后第 3 个请求状态 %s, 期望断路", results[2].Status)
+	}
+}
+
+// 打开或半开期间, 非探测请求的结果不能关闭断路器或让出探测名额
+func TestBreakerStaleResults(t *testing.T) {
+	b := NewCircuitBreaker(1, time.Minute)
+	t0 := time.Unix(1000, 0)
+
+	ok, _ := b.allow("h", t0) // 打开前已在途的请求
+	if !ok {
+		t.Fatal("关闭状态应放行")
+	}
+	b.record("h", StatusFailed, errors.New("refused"), false, t0)
+	b.record("h", StatusSuccess, nil, false, t0) // 迟到的在途结果
+	if ok, _ := b.allow("h", t0.Add(time.Second)); ok {
+		t.Fatal("迟到的成功结果关闭了断路器")
+	}
+
+	half := t0.Add(2 * time.Minute)
+	ok, probe := b.allow("h", half)
+	if !ok || !probe {
+		t.Fatalf("冷却结束应放行一个探测请求, 得到 ok=%v probe=%v", ok, probe)
+	}
+	b.record("h", StatusFailed, errors.New("refused"), false, half) // 迟到的非探测结果
+	if ok, _ := b.allow("h", half); ok {
+		t.Fatal("非探测结果让出了探测名额")
+	}
+	b.record("h", StatusSuccess, nil, true, half)
+	if ok, probe := b.allow("h", half); !ok || probe {
+		t.Fatalf("探测成功后应关闭, 得到 ok=%v probe=%v", ok, probe)
+	}
+}
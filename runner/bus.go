@@ -0,0 +1,97 @@
+package runner
+
+import "sync"
+
+// Runner 发布结果所用的主题
+const (
+	TopicResult  = "result"  // 按完成顺序发布每个结果
+	TopicOrdered = "ordered" // 按提交顺序发布就绪的结果
+)
+
+// Bus 是按主题发布/订阅的进程内事件总线。每个订阅者有独立的缓冲通道,
+// 发布时逐个投递, 某个订阅者缓冲已满时发布方等待它(背压), 不丢事件
+type Bus[E any] struct {
+	mu     sync.RWMutex
+	subs   map[string][]*Subscription[E]
+	closed bool
+}
+
+// Subscription 是一个订阅, 从 C 读取事件; 总线关闭或取消订阅后 C 被关闭
+type Subscription[E any] struct {
+	C <-chan E
+
+	topic string
+	ch    chan E
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewBus 创建事件总线
+func NewBus[E any]() *Bus[E] {
+	return &Bus[E]{subs: make(map[string][]*Subscription[E])}
+}
+
+// Subscribe 订阅 topic, buffer 为该订阅者的缓冲大小
+func (b *Bus[E]) Subscribe(topic string, buffer int) *Subscription[E] {
+	ch := make(chan E, buffer)
+	s := &Subscription[E]{C: ch, topic: topic, ch: ch, done: make(chan struct{})}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		s.stop()
+		close(s.ch)
+		return s
+	}
+	b.subs[topic] = append(b.subs[topic], s)
+	return s
+}
+
+// Unsubscribe 取消订阅并关闭其通道, 阻塞在该订阅者上的发布会立即放弃它
+func (b *Bus[E]) Unsubscribe(s *Subscription[E]) {
+	s.stop()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs := b.subs[s.topic]
+	for i, sub := range subs {
+		if sub == s {
+			b.subs[s.topic] = append(subs[:i:i], subs[i+1:]...)
+			close(s.ch)
+			return
+		}
+	}
+}
+
+// Publish 把 e 投递给 topic 的所有订阅者, 总线关闭后的发布被忽略
+func (b *Bus[E]) Publish(topic string, e E) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, s := range b.subs[topic] {
+		select {
+		case s.ch <- e:
+		case <-s.done:
+		}
+	}
+}
+
+// Close 关闭总线和所有订阅者的通道
+func (b *Bus[E]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for _, subs := range b.subs {
+		for _, s := range subs {
+			s.stop()
+			close(s.ch)
+		}
+	}
+	b.subs = nil
+}
+
+func (s *Subscription[E]) stop() {
+	s.once.Do(func() { close(s.done) })
+}
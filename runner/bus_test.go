@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"testing"
+	"time"
+)
+
+// 每个订阅者按发布顺序收到自己主题的全部事件, 缓冲已满时发布方等待而不丢事件
+func TestBusPublish(t *testing.T) {
+	b := NewBus[int]()
+	slow := b.Subscribe(TopicResult, 0)
+	fast := b.Subscribe(TopicResult, 16)
+	other := b.Subscribe(TopicOrdered, 1)
+
+	const n = 10
+	go func() {
+		for i := 0; i < n; i++ {
+			b.Publish(TopicResult, i)
+		}
+		b.Close()
+	}()
+
+	for _, s := range []*Subscription[int]{slow, fast} {
+		var got []int
+		for e := range s.C {
+			got = append(got, e)
+		}
+		if len(got) != n {
+			t.Fatalf("收到 %d 个事件, 期望 %d 个", len(got), n)
+		}
+		for i, e := range got {
+			if e != i {
+				t.Fatalf("第 %d 个事件为 %d, 顺序错乱: %v", i, e, got)
+			}
+		}
+	}
+	if _, ok := <-other.C; ok {
+		t.Error("其他主题的订阅者收到了事件")
+	}
+}
+
+// 取消订阅会放弃阻塞在该订阅者上的发布; 关闭后的订阅与发布不阻塞
+func TestBusUnsubscribeAndClose(t *testing.T) {
+	b := NewBus[int]()
+	s := b.Subscribe(TopicResult, 0)
+
+	published := make(chan struct{})
+	go func() {
+		b.Publish(TopicResult, 1) // 无人读取, 阻塞到取消订阅
+		close(published)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	b.Unsubscribe(s)
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("取消订阅后发布仍然阻塞")
+	}
+	if _, ok := <-s.C; ok {
+		t.Error("取消订阅后通道未关闭")
+	}
+
+	b.Close()
+	b.Close()
+	late := b.Subscribe(TopicResult, 1)
+	b.Publish(TopicResult, 2)
+	if _, ok := <-late.C; ok {
+		t.Error("关闭后订阅的通道应已关闭")
+	}
+}
@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// 请求体压缩算法注册表, 名称即 Content-Encoding 的取值
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[string]func(io.Writer) (io.WriteCloser, error){
+		"gzip": func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriter(w), nil
+		},
+		// HTTP 的 deflate 指 zlib 格式(RFC 9110 §8.4.1.2), 而非裸 DEFLATE 流
+		"deflate": func(w io.Writer) (io.WriteCloser, error) {
+			return zlib.NewWriterLevel(w, zlib.DefaultCompression)
+		},
+	}
+)
+
+// RegisterEncoding 注册(或替换)名为 name 的请求体压缩算法, 供 HTTPExecutor.Encoding 使用;
+// 内置 gzip 与 deflate, 其他算法(如 zstd)只能由库的调用方注册, 命令行的 -encoding 无法选用
+func RegisterEncoding(name string, newWriter func(io.Writer) (io.WriteCloser, error)) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	encoders[name] = newWriter
+}
+
+// Encodings 返回已注册的压缩算法名称
+func Encodings() []string {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	names := make([]string, 0, len(encoders))
+	for name := range encoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// encodeBody 用名为 name 的算法压缩 body
+func encodeBody(name string, body []byte) ([]byte, error) {
+	encodersMu.RLock()
+	newWriter, ok := encoders[name]
+	encodersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("未注册的压缩算法 %q", name)
+	}
+
+	var buf bytes.Buffer
+	w, err := newWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
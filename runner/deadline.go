@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 截止时间传播所用的请求头
+const (
+	HeaderRequestTimeout = "X-Request-Timeout" // 剩余预算, 单位毫秒
+	HeaderGRPCTimeout    = "grpc-timeout"      // gRPC 格式: 最多 8 位数字 + 单位
+)
+
+// setDeadlineHeaders 按 deadline 相对 now 的剩余时间写入超时请求头, 已过期时不写
+func setDeadlineHeaders(h http.Header, deadline, now time.Time) {
+	remaining := deadline.Sub(now)
+	if remaining <= 0 {
+		return
+	}
+	ms := remaining.Milliseconds()
+	if ms == 0 {
+		ms = 1
+	}
+	h.Set(HeaderRequestTimeout, strconv.FormatInt(ms, 10))
+	h.Set(HeaderGRPCTimeout, formatGRPCTimeout(remaining))
+}
+
+// gRPC 超时单位, 由细到粗
+var grpcUnits = []struct {
+	unit byte
+	d    time.Duration
+}{
+	{'n', time.Nanosecond},
+	{'u', time.Microsecond},
+	{'m', time.Millisecond},
+	{'S', time.Second},
+	{'M', time.Minute},
+	{'H', time.Hour},
+}
+
+// formatGRPCTimeout 选取能用不超过 8 位数字表示 d 的最细单位(向上取整, 不缩短预算)
+func formatGRPCTimeout(d time.Duration) string {
+	const maxValue = 99999999
+	for _, u := range grpcUnits[2:] { // 从毫秒开始, 更细的精度对网络请求没有意义
+		v := (d + u.d - 1) / u.d
+		if v <= maxValue {
+			return strconv.FormatInt(int64(v), 10) + string(u.unit)
+		}
+	}
+	return strconv.Itoa(maxValue) + "H"
+}
+
+// ParseDeadlineHeader 从请求头中读出上游传来的剩余预算, 优先 grpc-timeout, 其次 X-Request-Timeout;
+// 供代理/服务端按上游预算收紧自己的超时
+func ParseDeadlineHeader(h http.Header) (time.Duration, bool) {
+	if v := h.Get(HeaderGRPCTimeout); len(v) >= 2 && len(v) <= 9 {
+		n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+		if err == nil && n > 0 {
+			for _, u := range grpcUnits {
+				if u.unit == v[len(v)-1] {
+					return time.Duration(n) * u.d, true
+				}
+			}
+		}
+	}
+	if v := h.Get(HeaderRequestTimeout); v != "" {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err == nil && ms > 0 {
+			return time.Duration(ms) * time.Millisecond, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,23 @@
+package runner
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+)
+
+// ExhaustionHint 是遇到文件描述符/临时端口耗尽时的处理建议
+const ExhaustionHint = "调高 ulimit -n、降低并发数, 或复用连接(启用 keep-alive、调大 MaxIdleConnsPerHost)"
+
+// exhausted 判断错误是否由本机文件描述符或临时端口耗尽引起, 而非目标服务的问题
+func exhausted(err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, syscall.EMFILE), errors.Is(err, syscall.ENFILE), errors.Is(err, syscall.EADDRNOTAVAIL):
+		return true
+	}
+	// 部分平台/包装层只保留了错误文本
+	msg := err.Error()
+	return strings.Contains(msg, "too many open files") || strings.Contains(msg, "cannot assign requested address")
+}
@@ -0,0 +1,12 @@
+package runner
+
+import "net/url"
+
+// hostOf 返回任务 URL 的主机部分, 无法解析时返回原始字符串, 用于按主机分组
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return u.Host
+}
@@ -0,0 +1,239 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	mrand "math/rand"
+	"net/http"
+	"time"
+)
+
+// HTTPResponse 是 HTTPExecutor 完成一次请求后的结果数据
+type HTTPResponse struct {
+	StatusCode int         // HTTP 状态码
+	Size       int64       // 响应体字节数
+	Header     http.Header // 响应头
+	FinalURL   string      // 跟随重定向后的最终地址
+	Redirects  int         // 经过的重定向次数
+
+	BodySize        int    // 请求体原始字节数
+	EncodedBodySize int    // 请求体实际发送的字节数(未压缩时与 BodySize 相同)
+	ContentEncoding string // 响应的 Content-Encoding
+	Decompressed    bool   // 响应体是否已被 net/http 透明解压(此时 Size 为解压后的大小)
+
+	RequestID string // 发出的请求 ID(设置 RequestIDHeader 时)
+	EchoedID  string // 响应头中回显的请求 ID
+
+	Body          []byte // 被采样保留的响应体, 见 HTTPExecutor.CaptureRate; 未采样时为 nil
+	BodyTruncated bool   // 保留的响应体是否因 MaxCapture 被截断
+}
+
+func (r HTTPResponse) String() string {
+	s := fmt.Sprintf("HTTP %d (%d 字节)", r.StatusCode, r.Size)
+	if r.EchoMismatch() {
+		s += fmt.Sprintf(" 请求 ID 未回显 (发送 %s, 收到 %q)", r.RequestID, r.EchoedID)
+	}
+	return s
+}
+
+// EchoMismatch 报告响应是否没有原样回显请求 ID, 通常说明响应来自缓存或中间层而非源站
+func (r HTTPResponse) EchoMismatch() bool {
+	return r.RequestID != "" && r.EchoedID != r.RequestID
+}
+
+// StatusError 是服务端返回 >= 400 状态码时的错误: 请求已被服务端受理并作答
+type StatusError struct {
+	URL  string
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("请求失败 [%s]: HTTP %d", e.URL, e.Code)
+}
+
+// Overloaded 报告状态码是否表示服务端过载(429、503), 自适应限流只把这类响应计为拒绝
+func (e *StatusError) Overloaded() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code == http.StatusServiceUnavailable
+}
+
+// HTTPExecutor 使用 net/http 真正发出请求, 零值即可使用(GET, http.DefaultClient)
+type HTTPExecutor struct {
+	Client *http.Client // 为 nil 时使用 http.DefaultClient
+	Method string       // 请求方法, 默认 GET
+	Body   []byte       // 请求体(POST 等方法使用)
+	Header http.Header  // 附加的请求头
+
+	// PropagateDeadline 为 true 时, 按任务 ctx 的截止时间发送 X-Request-Timeout 与 grpc-timeout 头,
+	// 让能感知预算的后端提前放弃注定超时的请求
+	PropagateDeadline bool
+
+	// Encoding 不为空时用该算法压缩请求体并设置 Content-Encoding, 见 RegisterEncoding
+	Encoding string
+	// AcceptEncoding 不为空时原样作为 Accept-Encoding 发送, 响应体不再被透明解压, Size 即传输的字节数;
+	// 为空时由 net/http 自动协商 gzip 并透明解压
+	AcceptEncoding string
+
+	// RequestIDHeader 不为空时, 每次请求在该头中发送唯一 ID, 并校验响应是否在同名头中回显
+	RequestIDHeader string
+
+	// CaptureRate 是保留完整响应体的采样比例(0~1), CaptureFailures 为 true 时状态码 >= 400 的响应总是保留;
+	// MaxCapture 限制每个响应保留的字节数(0 表示不限), 让大批量运行的存储量可控
+	CaptureRate     float64
+	CaptureFailures bool
+	MaxCapture      int
+}
+
+// Task 构造一个请求 url 的任务
+func (e *HTTPExecutor) Task(url string) Task[HTTPResponse] {
+	return Task[HTTPResponse]{
+		URL: url,
+		Do: func(ctx context.Context) (HTTPResponse, error) {
+			return e.Do(ctx, url)
+		},
+	}
+}
+
+// With 返回覆盖了请求方法(为空时沿用)与请求头的副本, header 中出现的每个键整体替换公共请求头中的同名键; 原执行器不变
+func (e *HTTPExecutor) With(method string, header http.Header) *HTTPExecutor {
+	c := *e
+	if method != "" {
+		c.Method = method
+	}
+	if len(header) > 0 {
+		c.Header = e.Header.Clone()
+		if c.Header == nil {
+			c.Header = make(http.Header)
+		}
+		for k, vs := range header {
+			c.Header.Del(k)
+			for _, v := range vs {
+				c.Header.Add(k, v)
+			}
+		}
+	}
+	return &c
+}
+
+// Do 对 url 发出一次请求并读完响应体; 状态码 >= 400 时在返回响应的同时返回错误
+func (e *HTTPExecutor) Do(ctx context.Context, url string) (HTTPResponse, error) {
+	method := e.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	payload := e.Body
+	if e.Encoding != "" && payload != nil {
+		encoded, err := encodeBody(e.Encoding, payload)
+		if err != nil {
+			return HTTPResponse{}, err
+		}
+		payload = encoded
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return HTTPResponse{}, err
+	}
+	for k, vs := range e.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if e.Encoding != "" && payload != nil {
+		req.Header.Set("Content-Encoding", e.Encoding)
+	}
+	if e.AcceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", e.AcceptEncoding)
+	}
+	if deadline, ok := ctx.Deadline(); ok && e.PropagateDeadline {
+		setDeadlineHeaders(req.Header, deadline, time.Now())
+	}
+
+	var requestID string
+	if e.RequestIDHeader != "" {
+		requestID = newRequestID()
+		req.Header.Set(e.RequestIDHeader, requestID)
+	}
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return HTTPResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var captured []byte
+	var truncated bool
+	var n int64
+	if e.captures(resp.StatusCode) {
+		captured, truncated, n, err = readCapture(resp.Body, e.MaxCapture)
+	} else {
+		n, err = io.Copy(io.Discard, resp.Body)
+	}
+	result := HTTPResponse{
+		StatusCode:      resp.StatusCode,
+		Size:            n,
+		Header:          resp.Header,
+		BodySize:        len(e.Body),
+		EncodedBodySize: len(payload),
+		ContentEncoding: resp.Header.Get("Content-Encoding"),
+		Decompressed:    resp.Uncompressed,
+		RequestID:       requestID,
+		Body:            captured,
+		BodyTruncated:   truncated,
+	}
+	result.FinalURL = resp.Request.URL.String()
+	for r := resp.Request; r.Response != nil; r = r.Response.Request {
+		result.Redirects++
+	}
+	if requestID != "" {
+		result.EchoedID = resp.Header.Get(e.RequestIDHeader)
+	}
+	if err != nil {
+		return result, fmt.Errorf("读取响应失败 [%s]: %w", url, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return result, &StatusError{URL: url, Code: resp.StatusCode}
+	}
+	return result, nil
+}
+
+// captures 判断状态码为 code 的响应是否保留响应体
+func (e *HTTPExecutor) captures(code int) bool {
+	if e.CaptureFailures && code >= http.StatusBadRequest {
+		return true
+	}
+	return e.CaptureRate > 0 && mrand.Float64() < e.CaptureRate
+}
+
+// readCapture 读完 r, 保留至多 limit 字节(limit 为 0 时全部保留), 返回保留的内容、是否截断与总字节数
+func readCapture(r io.Reader, limit int) ([]byte, bool, int64, error) {
+	var buf bytes.Buffer
+	src := r
+	if limit > 0 {
+		src = io.LimitReader(r, int64(limit))
+	}
+	n, err := io.Copy(&buf, src)
+	if err != nil {
+		return buf.Bytes(), false, n, err
+	}
+	rest, err := io.Copy(io.Discard, r)
+	return buf.Bytes(), rest > 0, n + rest, err
+}
+
+// newRequestID 生成 16 位十六进制的随机请求 ID
+func newRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
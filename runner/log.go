@@ -0,0 +1,37 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+)
+
+// logEvent 在设置了 Logger 时记录一条任务事件
+func (c *Config) logEvent(ctx context.Context, level slog.Level, event string, attrs ...slog.Attr) {
+	if c.Logger == nil || !c.Logger.Enabled(ctx, level) {
+		return
+	}
+	c.Logger.LogAttrs(ctx, level, event, attrs...)
+}
+
+// logResult 记录任务的最终结果: 取消记为 task_cancel, 其余记为 task_done(出错时为 Warn 级别)
+func logResult[T any](ctx context.Context, c *Config, r Result[T]) {
+	if c.Logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.Int("index", r.Index),
+		slog.String("url", r.URL),
+		slog.String("status", r.Status),
+	}
+	if r.Status == StatusCanceled {
+		c.logEvent(ctx, slog.LevelInfo, "task_cancel", append(attrs, slog.Any("error", r.Err))...)
+		return
+	}
+	attrs = append(attrs, slog.Int("attempts", r.Attempts), slog.Duration("duration", r.Duration))
+	level := slog.LevelInfo
+	if r.Err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.Any("error", r.Err))
+	}
+	c.logEvent(ctx, level, "task_done", attrs...)
+}
@@ -0,0 +1,158 @@
+package runner
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Option 修改 Runner 的配置
+type Option func(*Config)
+
+// New 按 opts 创建 Runner; 不传任何选项时与零值 Runner 等价
+func New[T any](opts ...Option) *Runner[T] {
+	r := &Runner[T]{}
+	for _, opt := range opts {
+		opt(&r.Config)
+	}
+	return r
+}
+
+// WithConcurrency 设置同时执行的最大任务数, 0 表示每个任务一个协程
+func WithConcurrency(n int) Option {
+	return func(c *Config) {
+		c.Concurrency = n
+	}
+}
+
+// WithMaxPerHost 设置同一主机同时执行的最大任务数, 0 表示不限
+func WithMaxPerHost(n int) Option {
+	return func(c *Config) {
+		c.MaxPerHost = n
+	}
+}
+
+// WithJitter 设置每个任务启动前的随机抖动窗口
+func WithJitter(d time.Duration) Option {
+	return func(c *Config) {
+		c.Jitter = d
+	}
+}
+
+// WithTimeout 设置单个任务的超时时间, 超时的任务被取消并记为 StatusTimeout
+func WithTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.Timeout = d
+	}
+}
+
+// WithRateLimit 用令牌桶把任务启动限制为每 per 时长 n 个(不允许突发), n<=0 时不限流
+func WithRateLimit(n int, per time.Duration) Option {
+	return func(c *Config) {
+		c.RateLimit = nil
+		if n > 0 && per > 0 {
+			c.RateLimit = NewTokenBucket(n, per, 1)
+		}
+	}
+}
+
+// WithLimiter 使用指定的限流器控制任务启动, 见 NewLimiter
+func WithLimiter(l Limiter) Option {
+	return func(c *Config) {
+		c.RateLimit = l
+	}
+}
+
+// WithRetry 让失败或超时的任务最多重试 n 次, 等待从 100ms 起指数翻倍、上限 5s、抖动 20%;
+// 需要其他参数时使用 WithRetryPolicy
+func WithRetry(n int) Option {
+	return WithRetryPolicy(RetryPolicy{
+		MaxAttempts: n + 1,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.2,
+	})
+}
+
+// WithRetryPolicy 设置默认重试策略, 任务自带的 Task.Retry 优先
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Config) {
+		c.Retry = p
+	}
+}
+
+// WithAdaptiveThrottle 启用倍数为 k 的按主机客户端自适应限流
+func WithAdaptiveThrottle(k float64) Option {
+	return func(c *Config) {
+		c.Throttle = NewAdaptiveThrottle(k)
+	}
+}
+
+// WithCircuitBreaker 启用按主机的断路器: 连续 threshold 次出错后短路 cooldown 时长
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Config) {
+		c.Breaker = NewCircuitBreaker(threshold, cooldown)
+	}
+}
+
+// WithResourceLimits 设置工具自身的资源保护上限
+func WithResourceLimits(l ResourceLimits) Option {
+	return func(c *Config) {
+		c.Resources = &l
+	}
+}
+
+// WithAutoReduce 遇到本机资源耗尽时自动把 worker 数减半
+func WithAutoReduce() Option {
+	return func(c *Config) {
+		c.AutoReduce = true
+	}
+}
+
+// WithTracer 为运行和每个任务创建追踪 span
+func WithTracer(t Tracer) Option {
+	return func(c *Config) {
+		c.Tracer = t
+	}
+}
+
+// WithLogger 用 l 记录任务的结构化日志事件
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = l
+	}
+}
+
+// WithFailFast 启用快速失败: 任一任务出错即取消其余任务
+func WithFailFast() Option {
+	return func(c *Config) {
+		c.FailFast = true
+	}
+}
+
+// WithDeadline 限制整批运行的时长, 见 Config.Deadline
+func WithDeadline(d time.Duration) Option {
+	return func(c *Config) {
+		c.Deadline = d
+	}
+}
+
+// WithDrain 设置 ctx 取消后运行中任务的最长收尾时间, 见 Config.Drain
+func WithDrain(d time.Duration) Option {
+	return func(c *Config) {
+		c.Drain = d
+	}
+}
+
+// WithRaceFirst 启用竞速模式: 任一任务成功即取消其余任务, 适合向冗余端点发出同一请求
+func WithRaceFirst() Option {
+	return func(c *Config) {
+		c.RaceFirst = true
+	}
+}
+
+// WithStrict 启用严格模式, 运行时校验内部不变量
+func WithStrict() Option {
+	return func(c *Config) {
+		c.Strict = true
+	}
+}
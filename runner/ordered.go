@@ -0,0 +1,108 @@
+package runner
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// 结果索引校验失败时记录的错误, 见 OrderedCollector.Err
+var (
+	ErrDuplicateIndex  = errors.New("重复的结果索引")
+	ErrIndexOutOfRange = errors.New("结果索引越界")
+)
+
+// OrderedCollector 把按完成顺序到达的结果重组为提交顺序:
+// 结果按 Index 暂存, 一旦从 0 开始的前缀连续即立刻放行。零值即可使用, 非并发安全。
+// 重复或越界的索引不会覆盖已有结果, 而是被丢弃并记录, 通过 Err 取出
+type OrderedCollector[T any] struct {
+	Total int // 大于 0 时索引须小于 Total, 否则视为越界
+
+	pending resultHeap[T] // 已到达但前缀尚未连续的结果, 按 Index 组成的最小堆
+	held    map[int]bool  // pending 中的索引, 用于发现重复
+	next    int           // 下一个应放行的索引
+	errs    []error
+}
+
+// resultHeap 是按 Index 排序的最小堆, 每次收入/放行为 O(log n)
+type resultHeap[T any] []Result[T]
+
+func (h resultHeap[T]) Len() int           { return len(h) }
+func (h resultHeap[T]) Less(i, j int) bool { return h[i].Index < h[j].Index }
+func (h resultHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *resultHeap[T]) Push(x any)        { *h = append(*h, x.(Result[T])) }
+func (h *resultHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = Result[T]{} // 释放 Value 的引用
+	*h = old[:n-1]
+	return x
+}
+
+// Add 收入一个结果, 返回因此变为连续、可按顺序放行的结果(可能为空)
+func (c *OrderedCollector[T]) Add(result Result[T]) []Result[T] {
+	switch i := result.Index; {
+	case i < 0 || (c.Total > 0 && i >= c.Total):
+		c.errs = append(c.errs, fmt.Errorf("%w [#%d %s]", ErrIndexOutOfRange, i, result.URL))
+		return nil
+	case i < c.next || c.held[i]:
+		c.errs = append(c.errs, fmt.Errorf("%w [#%d %s]", ErrDuplicateIndex, i, result.URL))
+		return nil
+	}
+
+	// 正好是下一个: 不经过堆直接放行, 顺序到达时没有堆开销
+	if result.Index != c.next {
+		if c.held == nil {
+			c.held = make(map[int]bool)
+		}
+		c.held[result.Index] = true
+		heap.Push(&c.pending, result)
+		return nil
+	}
+
+	ready := []Result[T]{result}
+	c.next++
+	for len(c.pending) > 0 && c.pending[0].Index == c.next {
+		ready = append(ready, heap.Pop(&c.pending).(Result[T]))
+		delete(c.held, c.next)
+		c.next++
+	}
+	return ready
+}
+
+// Err 返回收入过程中发现的所有重复或越界索引(以 errors.Join 组合), 没有时返回 nil
+func (c *OrderedCollector[T]) Err() error {
+	return errors.Join(c.errs...)
+}
+
+// Pending 返回已到达但仍在等待前面结果的数量
+func (c *OrderedCollector[T]) Pending() int {
+	return len(c.pending)
+}
+
+// All 消费 in 直到其关闭, 按提交顺序产出结果; 调用方提前停止迭代时不再读取 in
+func (c *OrderedCollector[T]) All(in <-chan Result[T]) iter.Seq[Result[T]] {
+	return func(yield func(Result[T]) bool) {
+		for result := range in {
+			for _, res := range c.Add(result) {
+				if !yield(res) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Chan 在后台消费 in, 把按提交顺序重组的结果发送到返回的通道; in 关闭后该通道随之关闭
+func (c *OrderedCollector[T]) Chan(in <-chan Result[T]) <-chan Result[T] {
+	out := make(chan Result[T])
+	go func() {
+		defer close(out)
+		for res := range c.All(in) {
+			out <- res
+		}
+	}()
+	return out
+}
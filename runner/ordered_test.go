@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// 乱序到达的结果必须按 Index 连续放行, 且每个结果恰好放行一次
+func TestOrderedCollectorShuffled(t *testing.T) {
+	const n = 100000
+	var c OrderedCollector[int]
+	next := 0
+	for _, i := range rand.Perm(n) {
+		for _, res := range c.Add(Result[int]{Index: i, Value: i}) {
+			if res.Index != next || res.Value != next {
+				t.Fatalf("放行顺序错误: 期望 #%d, 得到 #%d", next, res.Index)
+			}
+			next++
+		}
+	}
+	if next != n || c.Pending() != 0 {
+		t.Fatalf("放行 %d 个, 仍暂存 %d 个, 期望放行 %d 个", next, c.Pending(), n)
+	}
+}
+
+// All 提前停止迭代时不再消费输入通道
+func TestOrderedCollectorAllStops(t *testing.T) {
+	in := make(chan Result[int], 3)
+	in <- Result[int]{Index: 1}
+	in <- Result[int]{Index: 0}
+	in <- Result[int]{Index: 2}
+	close(in)
+
+	var c OrderedCollector[int]
+	var got []int
+	for res := range c.All(in) {
+		got = append(got, res.Index)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if len(got) != 2 || got[0] != 0 || got[1] != 1 || len(in) != 1 {
+		t.Fatalf("得到 %v, 通道剩余 %d", got, len(in))
+	}
+}
+
+// 重复或越界的索引被丢弃并记录, 不覆盖已放行或暂存的结果
+func TestOrderedCollectorInvalidIndex(t *testing.T) {
+	c := OrderedCollector[int]{Total: 3}
+	c.Add(Result[int]{Index: 1, Value: 1})
+	c.Add(Result[int]{Index: 1, Value: -1}) // 与暂存的重复
+	c.Add(Result[int]{Index: 3})            // 越界
+	c.Add(Result[int]{Index: -1})           // 越界
+	ready := c.Add(Result[int]{Index: 0})
+	c.Add(Result[int]{Index: 0, Value: -1}) // 与已放行的重复
+
+	if len(ready) != 2 || ready[1].Value != 1 {
+		t.Fatalf("放行 %v, 期望 #0、#1 且 #1 未被覆盖", ready)
+	}
+	err := c.Err()
+	if !errors.Is(err, ErrDuplicateIndex) || !errors.Is(err, ErrIndexOutOfRange) {
+		t.Fatalf("Err() = %v, 期望同时包含重复与越界", err)
+	}
+	if n := len(c.errs); n != 4 {
+		t.Fatalf("记录了 %d 个错误, 期望 4 个", n)
+	}
+}
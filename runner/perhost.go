@@ -0,0 +1,86 @@
+package runner
+
+import "sync"
+
+// HostMetrics 是单个主机在一次运行中的计数
+type HostMetrics struct {
+	Tasks    int `json:"tasks"`    // 分发到该主机的任务数
+	Peak     int `json:"peak"`     // 同时执行的最大任务数
+	Deferred int `json:"deferred"` // 因达到 MaxPerHost 而被推迟分发的任务数
+}
+
+// hostGate 限制每个主机同时执行的任务数。
+// 主机已满的任务不阻塞分发器, 而是挂起在该主机的等待队列中,
+// 待同主机任务完成时把名额直接转交给它并放入 ready, 由分发器取走
+type hostGate struct {
+	max   int      // 每主机并发上限, 0 表示不限
+	hosts []string // 任务索引 -> 主机
+	ready chan int // 名额已转交、等待分发的任务; 容量为任务数, 释放方永不阻塞
+
+	mu      sync.Mutex
+	active  map[string]int
+	waiting map[string][]int
+	stats   map[string]*HostMetrics
+}
+
+func newHostGate[T any](max int, tasks []Task[T]) *hostGate {
+	g := &hostGate{
+		max:     max,
+		hosts:   make([]string, len(tasks)),
+		ready:   make(chan int, len(tasks)),
+		active:  make(map[string]int),
+		waiting: make(map[string][]int),
+		stats:   make(map[string]*HostMetrics),
+	}
+	for i, task := range tasks {
+		g.hosts[i] = hostOf(task.URL)
+	}
+	return g
+}
+
+// acquire 为任务 i 占用主机名额; 主机已满时把任务挂起并返回 false
+func (g *hostGate) acquire(i int) bool {
+	host := g.hosts[i]
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s := g.stats[host]
+	if s == nil {
+		s = &HostMetrics{}
+		g.stats[host] = s
+	}
+	s.Tasks++
+	if g.max > 0 && g.active[host] >= g.max {
+		s.Deferred++
+		g.waiting[host] = append(g.waiting[host], i)
+		return false
+	}
+	g.active[host]++
+	s.Peak = max(s.Peak, g.active[host])
+	return true
+}
+
+// release 归还任务 i 的主机名额, 有挂起任务时名额直接转交给最早的一个
+func (g *hostGate) release(i int) {
+	host := g.hosts[i]
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if q := g.waiting[host]; len(q) > 0 {
+		g.waiting[host] = q[1:]
+		g.ready <- q[0]
+		return
+	}
+	g.active[host]--
+}
+
+// metrics 返回各主机计数的快照
+func (g *hostGate) metrics() map[string]HostMetrics {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	m := make(map[string]HostMetrics, len(g.stats))
+	for host, s := range g.stats {
+		m[host] = *s
+	}
+	return m
+}
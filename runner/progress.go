@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+)
+
+// Progress 是运行过程中的进度快照, 每收到一个结果时通过 Runner.OnProgress 传出
+type Progress struct {
+	Completed int           // 已完成(含失败、取消)的任务数
+	Total     int           // 任务总数
+	Running   int           // 正在执行的任务数
+	Errors    int           // 已完成任务中出错(失败、超时、断路、本地限流等)的个数
+	Elapsed   time.Duration // 自运行开始的耗时
+	ETA       time.Duration // 按当前平均速度估算的剩余时间, 尚无法估算时为 0
+}
+
+// Percent 返回完成百分比
+func (p Progress) Percent() float64 {
+	if p.Total == 0 {
+		return 100
+	}
+	return float64(p.Completed) * 100 / float64(p.Total)
+}
+
+func (p Progress) String() string {
+	return fmt.Sprintf("%d/%d (%.0f%%) 运行中 %d 出错 %d 剩余约 %v",
+		p.Completed, p.Total, p.Percent(), p.Running, p.Errors, p.ETA.Round(time.Second))
+}
+
+// newProgress 根据已完成数和耗时估算剩余时间
+func newProgress(completed, total, running, errs int, elapsed time.Duration) Progress {
+	p := Progress{Completed: completed, Total: total, Running: running, Errors: errs, Elapsed: elapsed}
+	if completed > 0 && completed < total {
+		p.ETA = elapsed / time.Duration(completed) * time.Duration(total-completed)
+	}
+	return p
+}
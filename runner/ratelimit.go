@@ -0,0 +1,184 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Limiter 控制任务启动的速率
+type Limiter interface {
+	// Wait 阻塞直到允许启动下一个任务, ctx 先取消时返回错误
+	Wait(ctx context.Context) error
+}
+
+// 限流算法名称, 用于 NewLimiter
+const (
+	AlgoTokenBucket   = "token"   // 令牌桶: 允许 burst 个突发, 之后匀速
+	AlgoLeakyBucket   = "leaky"   // 漏桶: 严格匀速, 不允许突发
+	AlgoSlidingWindow = "sliding" // 滑动窗口: 任意 per 时长内至多 n 个, 窗口内可集中突发
+	AlgoGCRA          = "gcra"    // GCRA: 与令牌桶等价的到达时间算法, 状态只有一个时间点
+)
+
+// NewLimiter 按算法名称创建 n/per 速率的限流器, burst 仅对令牌桶和 GCRA 有效(<1 视为 1)
+func NewLimiter(algo string, n int, per time.Duration, burst int) (Limiter, error) {
+	if n <= 0 || per <= 0 {
+		return nil, fmt.Errorf("无效的速率 %d/%v", n, per)
+	}
+	switch algo {
+	case AlgoTokenBucket, "":
+		return NewTokenBucket(n, per, burst), nil
+	case AlgoLeakyBucket:
+		return NewLeakyBucket(n, per), nil
+	case AlgoSlidingWindow:
+		return NewSlidingWindow(n, per), nil
+	case AlgoGCRA:
+		return NewGCRA(n, per, burst), nil
+	}
+	return nil, fmt.Errorf("未知的限流算法 %q (可选 token、leaky、sliding、gcra)", algo)
+}
+
+// waitUntil 等待到 at 时刻, 期间可被取消
+func waitUntil(ctx context.Context, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return context.Cause(ctx)
+	}
+	return sleepContext(ctx, time.Until(at))
+}
+
+// TokenBucket 是令牌桶限流器: 桶容量 burst, 每 per/n 补充一个令牌
+type TokenBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+// NewTokenBucket 创建每 per 时长 n 个、最多突发 burst 个的令牌桶
+func NewTokenBucket(n int, per time.Duration, burst int) *TokenBucket {
+	return &TokenBucket{interval: per / time.Duration(n), burst: float64(max(burst, 1))}
+}
+
+func (l *TokenBucket) Wait(ctx context.Context) error {
+	return waitUntil(ctx, l.reserve(time.Now()))
+}
+
+// reserve 预留一个令牌并返回允许启动的时刻; 令牌不足时记为欠账, 由后续补充偿还
+func (l *TokenBucket) reserve(now time.Time) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.last.IsZero() {
+		l.tokens, l.last = l.burst, now
+	}
+	if now.After(l.last) {
+		l.tokens = min(l.burst, l.tokens+float64(now.Sub(l.last))/float64(l.interval))
+		l.last = now
+	}
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return now
+	}
+	return now.Add(time.Duration(-l.tokens * float64(l.interval)))
+}
+
+// LeakyBucket 是漏桶限流器: 任务以固定间隔 per/n 依次放行
+type LeakyBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewLeakyBucket 创建每 per 时长放行 n 个的漏桶
+func NewLeakyBucket(n int, per time.Duration) *LeakyBucket {
+	return &LeakyBucket{interval: per / time.Duration(n)}
+}
+
+func (l *LeakyBucket) Wait(ctx context.Context) error {
+	return waitUntil(ctx, l.reserve(time.Now()))
+}
+
+func (l *LeakyBucket) reserve(now time.Time) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	start := now
+	if l.next.After(start) {
+		start = l.next
+	}
+	l.next = start.Add(l.interval)
+	return start
+}
+
+// SlidingWindow 是滑动窗口(日志)限流器: 任意 window 时长内至多放行 n 个
+type SlidingWindow struct {
+	mu     sync.Mutex
+	window time.Duration
+	starts []time.Time // 最近 n 次放行时刻的环形缓冲
+	head   int
+}
+
+// NewSlidingWindow 创建任意 window 时长内至多 n 个的滑动窗口
+func NewSlidingWindow(n int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{window: window, starts: make([]time.Time, 0, n)}
+}
+
+func (l *SlidingWindow) Wait(ctx context.Context) error {
+	return waitUntil(ctx, l.reserve(time.Now()))
+}
+
+func (l *SlidingWindow) reserve(now time.Time) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.starts) < cap(l.starts) {
+		l.starts = append(l.starts, now)
+		return now
+	}
+
+	// 第 n 个之前的放行时刻滑出窗口后才能放行
+	start := now
+	if edge := l.starts[l.head].Add(l.window); edge.After(start) {
+		start = edge
+	}
+	l.starts[l.head] = start
+	l.head = (l.head + 1) % len(l.starts)
+	return start
+}
+
+// GCRA 是通用信元速率算法限流器, 行为与令牌桶一致, 但只记录理论到达时间(TAT)
+type GCRA struct {
+	mu        sync.Mutex
+	interval  time.Duration
+	tolerance time.Duration // 允许提前到达的时长, 即 (burst-1)*interval
+	tat       time.Time
+}
+
+// NewGCRA 创建每 per 时长 n 个、最多突发 burst 个的 GCRA 限流器
+func NewGCRA(n int, per time.Duration, burst int) *GCRA {
+	interval := per / time.Duration(n)
+	return &GCRA{interval: interval, tolerance: time.Duration(max(burst, 1)-1) * interval}
+}
+
+func (l *GCRA) Wait(ctx context.Context) error {
+	return waitUntil(ctx, l.reserve(time.Now()))
+}
+
+func (l *GCRA) reserve(now time.Time) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tat := l.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	start := now
+	if allowAt := tat.Add(-l.tolerance); allowAt.After(start) {
+		start = allowAt
+	}
+	l.tat = tat.Add(l.interval)
+	return start
+}
@@ -0,0 +1,158 @@
+package runner
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+type reserver interface {
+	reserve(now time.Time) time.Time
+}
+
+// reserveAll 让 count 个请求在 arrivals 指定的时刻到达, 返回各自的放行时刻
+func reserveAll(l reserver, arrivals []time.Time) []time.Time {
+	starts := make([]time.Time, len(arrivals))
+	for i, at := range arrivals {
+		starts[i] = l.reserve(at)
+	}
+	return starts
+}
+
+// sameArrival 返回 count 个同时到达的时刻
+func sameArrival(t0 time.Time, count int) []time.Time {
+	arrivals := make([]time.Time, count)
+	for i := range arrivals {
+		arrivals[i] = t0
+	}
+	return arrivals
+}
+
+// maxInWindow 返回任意长度为 window 的半开区间内放行的最大个数
+func maxInWindow(starts []time.Time, window time.Duration) int {
+	best := 0
+	for i := range starts {
+		n := 0
+		for _, s := range starts[i:] {
+			if s.Sub(starts[i]) < window {
+				n++
+			}
+		}
+		best = max(best, n)
+	}
+	return best
+}
+
+func TestLimiterShapes(t *testing.T) {
+	const (
+		n     = 10
+		per   = time.Second
+		burst = 5
+	)
+	interval := per / n
+	t0 := time.Unix(1000, 0)
+
+	tests := []struct {
+		name      string
+		limiter   reserver
+		wantStart func(i int) time.Duration // 第 i 个请求相对 t0 的放行时刻
+		maxPerWin int                       // 任意 per 窗口内的放行上限
+	}{
+		{
+			name:    "令牌桶: 先突发 burst 个, 之后匀速",
+			limiter: NewTokenBucket(n, per, burst),
+			wantStart: func(i int) time.Duration {
+				return time.Duration(max(i-burst+1, 0)) * interval
+			},
+			maxPerWin: n + burst - 1,
+		},
+		{
+			name:    "GCRA: 与令牌桶一致",
+			limiter: NewGCRA(n, per, burst),
+			wantStart: func(i int) time.Duration {
+				return time.Duration(max(i-burst+1, 0)) * interval
+			},
+			maxPerWin: n + burst - 1,
+		},
+		{
+			name:    "漏桶: 严格匀速, 无突发",
+			limiter: NewLeakyBucket(n, per),
+			wantStart: func(i int) time.Duration {
+				return time.Duration(i) * interval
+			},
+			maxPerWin: n,
+		},
+		{
+			name:    "滑动窗口: 每个窗口开头集中放行 n 个",
+			limiter: NewSlidingWindow(n, per),
+			wantStart: func(i int) time.Duration {
+				return time.Duration(i/n) * per
+			},
+			maxPerWin: n,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			starts := reserveAll(tt.limiter, sameArrival(t0, 3*n))
+			for i, s := range starts {
+				if got, want := s.Sub(t0), tt.wantStart(i); got != want {
+					t.Errorf("第 %d 个放行于 %v, 期望 %v", i, got, want)
+				}
+			}
+			if got := maxInWindow(starts, per); got > tt.maxPerWin {
+				t.Errorf("单个窗口内放行 %d 个, 上限 %d", got, tt.maxPerWin)
+			}
+		})
+	}
+}
+
+func TestLimiterRefillAfterIdle(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	limiters := map[string]reserver{
+		AlgoTokenBucket:   NewTokenBucket(10, time.Second, 5),
+		AlgoGCRA:          NewGCRA(10, time.Second, 5),
+		AlgoSlidingWindow: NewSlidingWindow(10, time.Second),
+	}
+	for name, l := range limiters {
+		reserveAll(l, sameArrival(t0, 20))
+
+		// 空闲足够久后, 突发额度应完全恢复
+		idle := t0.Add(time.Hour)
+		starts := reserveAll(l, sameArrival(idle, 5))
+		for i, s := range starts {
+			if !s.Equal(idle) {
+				t.Errorf("%s: 空闲后第 %d 个放行延迟了 %v", name, i, s.Sub(idle))
+			}
+		}
+	}
+}
+
+func TestGCRAMatchesTokenBucket(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tb := NewTokenBucket(50, time.Second, 8)
+	gcra := NewGCRA(50, time.Second, 8)
+
+	now := time.Unix(1000, 0)
+	for i := 0; i < 1000; i++ {
+		now = now.Add(time.Duration(rng.Int63n(int64(40 * time.Millisecond))))
+		a, b := tb.reserve(now), gcra.reserve(now)
+		if d := a.Sub(b); d > time.Microsecond || d < -time.Microsecond {
+			t.Fatalf("第 %d 个请求: 令牌桶放行于 %v, GCRA 放行于 %v", i, a.Sub(now), b.Sub(now))
+		}
+	}
+}
+
+func TestNewLimiter(t *testing.T) {
+	for _, algo := range []string{AlgoTokenBucket, AlgoLeakyBucket, AlgoSlidingWindow, AlgoGCRA} {
+		if _, err := NewLimiter(algo, 10, time.Second, 1); err != nil {
+			t.Errorf("NewLimiter(%q): %v", algo, err)
+		}
+	}
+	if _, err := NewLimiter("fifo", 10, time.Second, 1); err == nil {
+		t.Error("未知算法应返回错误")
+	}
+	if _, err := NewLimiter(AlgoTokenBucket, 0, time.Second, 1); err == nil {
+		t.Error("速率为 0 应返回错误")
+	}
+}
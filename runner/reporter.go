@@ -0,0 +1,125 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Reporter 负责展示运行结果, 与执行逻辑解耦; 实现它即可把结果写到终端、文件或数据库等任意位置。
+// 三个方法都在聚合协程中串行调用, 实现无需加锁
+type Reporter[T any] interface {
+	OnStart(total int)   // 分发任务前调用, total 为任务总数
+	OnResult(Result[T])  // 每收到一个结果时调用(按完成顺序)
+	OnFinish(Summary[T]) // 所有结果就绪后、Run 返回前调用
+}
+
+// Summary 是一次运行结束时交给 Reporter.OnFinish 的汇总
+type Summary[T any] struct {
+	Results []Result[T]   // 按提交顺序排列的结果
+	Stats   Stats         // 耗时统计, 见 ComputeStats
+	Metrics Metrics       // 运行器内部指标, 同 Runner.Metrics
+	Elapsed time.Duration // 整批运行的耗时
+	Err     error         // RunWithContext 将返回的错误(如快速失败)
+}
+
+// Succeeded 返回成功的结果数
+func (s Summary[T]) Succeeded() int {
+	n := 0
+	for _, r := range s.Results {
+		if r.Err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// SilentReporter 丢弃所有事件, 用于只关心 Run 返回值的场景
+type SilentReporter[T any] struct{}
+
+func (SilentReporter[T]) OnStart(int)         {}
+func (SilentReporter[T]) OnResult(Result[T])  {}
+func (SilentReporter[T]) OnFinish(Summary[T]) {}
+
+// TableReporter 按完成顺序逐行打印结果表, 结束时打印一行汇总
+type TableReporter[T any] struct {
+	W io.Writer
+}
+
+func (t TableReporter[T]) OnStart(total int) {
+	fmt.Fprintf(t.W, "共 %d 个任务\n", total)
+	fmt.Fprintf(t.W, "%-5s %-12s %-8s %-4s %-45s %s\n", "序号", "耗时", "状态", "次数", "请求地址", "详情")
+}
+
+func (t TableReporter[T]) OnResult(r Result[T]) {
+	fmt.Fprintf(t.W, "%-5d %-12v %-8s %-4d %-45s ", r.Index, r.Duration, r.Status, r.Attempts, r.URL)
+	if r.Err != nil {
+		fmt.Fprintf(t.W, "❌ %v\n", r.Err)
+	} else {
+		fmt.Fprintf(t.W, "✅ %v\n", r.Value)
+	}
+}
+
+func (t TableReporter[T]) OnFinish(s Summary[T]) {
+	fmt.Fprintf(t.W, "完成 %d/%d, 耗时 %v, p50 %v, p99 %v\n",
+		s.Succeeded(), len(s.Results), s.Elapsed, s.Stats.P50, s.Stats.P99)
+	if s.Err != nil {
+		fmt.Fprintf(t.W, "⛔ %v\n", s.Err)
+	}
+}
+
+// JSONReporter 在运行结束时把结果与统计作为一个 JSON 对象写入 W, 结果值 T 按 encoding/json 规则编码
+type JSONReporter[T any] struct {
+	W io.Writer
+}
+
+func (JSONReporter[T]) OnStart(int)        {}
+func (JSONReporter[T]) OnResult(Result[T]) {}
+
+func (j JSONReporter[T]) OnFinish(s Summary[T]) {
+	type result struct {
+		Index      int     `json:"index"`
+		URL        string  `json:"url"`
+		Status     string  `json:"status"`
+		DurationMS float64 `json:"duration_ms"`
+		Attempts   int     `json:"attempts"`
+		Value      T       `json:"value"`
+		Error      string  `json:"error,omitempty"`
+	}
+	report := struct {
+		Results   []result `json:"results"`
+		Total     int      `json:"total"`
+		Success   int      `json:"success"`
+		ElapsedMS float64  `json:"elapsed_ms"`
+		P50MS     float64  `json:"p50_ms"`
+		P99MS     float64  `json:"p99_ms"`
+		Error     string   `json:"error,omitempty"`
+	}{
+		Results:   make([]result, len(s.Results)),
+		Total:     len(s.Results),
+		Success:   s.Succeeded(),
+		ElapsedMS: s.Elapsed.Seconds() * 1000,
+		P50MS:     s.Stats.P50.Seconds() * 1000,
+		P99MS:     s.Stats.P99.Seconds() * 1000,
+	}
+	for i, r := range s.Results {
+		report.Results[i] = result{
+			Index:      r.Index,
+			URL:        r.URL,
+			Status:     r.Status,
+			DurationMS: r.Duration.Seconds() * 1000,
+			Attempts:   r.Attempts,
+			Value:      r.Value,
+		}
+		if r.Err != nil {
+			report.Results[i].Error = r.Err.Error()
+		}
+	}
+	if s.Err != nil {
+		report.Error = s.Err.Error()
+	}
+	enc := json.NewEncoder(j.W)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+}
@@ -0,0 +1,161 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+)
+
+// ResourceLimits 是工具自身的资源保护上限: 接近上限时分发器暂停启动新任务,
+// 直到在途任务完成、资源回落, 使配置失当的超大批量运行平稳降速而不是耗尽主机资源。
+// 没有在途任务时总是放行, 因此上限再低也只会降速而不会卡死; 上限低于运行开始时的基线占用时,
+// 按基线加上单个任务的余量放宽。各项为 0 表示不检查
+type ResourceLimits struct {
+	MaxGoroutines int           // 进程内协程数上限; 同时把 worker 数限制在其一半以内
+	MaxOpenFiles  int           // 打开的文件描述符上限(通过 /proc/self/fd 统计, 不支持的平台跳过)
+	MaxMemory     uint64        // 运行时向操作系统申请的内存估算上限(字节)
+	Interval      time.Duration // 采样间隔, 为 0 时取 10ms
+}
+
+// ResourceUsage 是一次资源采样
+type ResourceUsage struct {
+	Goroutines int
+	OpenFiles  int // -1 表示无法统计
+	Memory     uint64
+}
+
+func (u ResourceUsage) String() string {
+	return fmt.Sprintf("协程 %d, 文件描述符 %d, 内存 %.1f MiB", u.Goroutines, u.OpenFiles, float64(u.Memory)/(1<<20))
+}
+
+// SampleResources 采集当前进程的资源占用
+func SampleResources() ResourceUsage {
+	u := ResourceUsage{Goroutines: runtime.NumGoroutine(), OpenFiles: -1}
+	if entries, err := os.ReadDir("/proc/self/fd"); err == nil {
+		u.OpenFiles = len(entries)
+	}
+
+	// 运行时占用的全部内存减去已归还操作系统的部分, 近似常驻内存
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() == metrics.KindUint64 && samples[1].Value.Kind() == metrics.KindUint64 {
+		u.Memory = samples[0].Value.Uint64() - samples[1].Value.Uint64()
+	}
+	return u
+}
+
+// exceeded 返回超出的那一项资源的描述, 均未超出时返回空串
+func (l *ResourceLimits) exceeded(u ResourceUsage) string {
+	switch {
+	case l.MaxGoroutines > 0 && u.Goroutines >= l.MaxGoroutines:
+		return fmt.Sprintf("协程数 %d 达到上限 %d", u.Goroutines, l.MaxGoroutines)
+	case l.MaxOpenFiles > 0 && u.OpenFiles >= l.MaxOpenFiles:
+		return fmt.Sprintf("文件描述符 %d 达到上限 %d", u.OpenFiles, l.MaxOpenFiles)
+	case l.MaxMemory > 0 && u.Memory >= l.MaxMemory:
+		return fmt.Sprintf("内存 %.1f MiB 达到上限 %.1f MiB", float64(u.Memory)/(1<<20), float64(l.MaxMemory)/(1<<20))
+	}
+	return ""
+}
+
+// 上限低于基线时, 为单个在途任务预留的余量
+const (
+	goroutineHeadroom = 4
+	openFileHeadroom  = 4
+	memoryHeadroom    = 16 << 20
+)
+
+// above 返回不低于基线 u 加余量的上限副本, 以及被放宽时的说明
+func (l *ResourceLimits) above(u ResourceUsage) (ResourceLimits, string) {
+	adjusted := *l
+	var note string
+	if l.MaxGoroutines > 0 && u.Goroutines >= l.MaxGoroutines {
+		adjusted.MaxGoroutines = u.Goroutines + goroutineHeadroom
+		note = fmt.Sprintf("协程上限 %d 低于基线 %d, 放宽为 %d", l.MaxGoroutines, u.Goroutines, adjusted.MaxGoroutines)
+	}
+	if l.MaxOpenFiles > 0 && u.OpenFiles >= l.MaxOpenFiles {
+		adjusted.MaxOpenFiles = u.OpenFiles + openFileHeadroom
+		note = fmt.Sprintf("文件描述符上限 %d 低于基线 %d, 放宽为 %d", l.MaxOpenFiles, u.OpenFiles, adjusted.MaxOpenFiles)
+	}
+	if l.MaxMemory > 0 && u.Memory >= l.MaxMemory {
+		adjusted.MaxMemory = u.Memory + memoryHeadroom
+		note = fmt.Sprintf("内存上限 %.1f MiB 低于基线 %.1f MiB, 放宽为 %.1f MiB",
+			float64(l.MaxMemory)/(1<<20), float64(u.Memory)/(1<<20), float64(adjusted.MaxMemory)/(1<<20))
+	}
+	return adjusted, note
+}
+
+// workers 按协程上限收紧 worker 数: 每个运行中的任务至少占用 worker 与执行两个协程
+func (l *ResourceLimits) workers(n int) int {
+	if l.MaxGoroutines > 0 {
+		n = min(n, max(l.MaxGoroutines/2, 1))
+	}
+	return n
+}
+
+// resourceGuard 在分发前检查资源, 超限时按采样间隔轮询等待
+type resourceGuard struct {
+	limits    *ResourceLimits
+	running   *atomic.Int64 // 在途任务数, 为 0 时总是放行
+	effective *ResourceLimits
+	waits     int           // 因资源超限而暂停分发的次数
+	waitTime  time.Duration // 累计暂停时长
+	peak      ResourceUsage // 采样到的峰值
+	lastCheck time.Time
+	reason    string // 最近一次暂停的原因
+	adjusted  string // 上限低于基线而被放宽时的说明
+}
+
+// sample 采样并更新峰值; 首次采样作为基线, 据此确定实际生效的上限
+func (g *resourceGuard) sample() ResourceUsage {
+	g.lastCheck = time.Now()
+	u := SampleResources()
+	g.peak.Goroutines = max(g.peak.Goroutines, u.Goroutines)
+	g.peak.OpenFiles = max(g.peak.OpenFiles, u.OpenFiles)
+	g.peak.Memory = max(g.peak.Memory, u.Memory)
+	if g.effective == nil {
+		adjusted, note := g.limits.above(u)
+		g.effective, g.adjusted = &adjusted, note
+	}
+	return u
+}
+
+// wait 在资源低于上限或没有在途任务前阻塞; 距上次采样不足一个间隔时直接放行, 避免每个任务都采样
+func (g *resourceGuard) wait(ctx context.Context) error {
+	if g.limits == nil {
+		return nil
+	}
+	interval := g.limits.Interval
+	if interval <= 0 {
+		interval = 10 * time.Millisecond
+	}
+	if now := time.Now(); g.effective != nil && now.Sub(g.lastCheck) < interval {
+		return nil
+	}
+
+	start := time.Now()
+	for paused := false; ; paused = true {
+		u := g.sample()
+		reason := g.effective.exceeded(u)
+		if reason == "" || g.running.Load() == 0 {
+			if paused {
+				g.waitTime += time.Since(start)
+			}
+			return nil
+		}
+		if !paused {
+			g.waits++
+			g.reason = reason
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			g.waitTime += time.Since(start)
+			return err
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// 上限低于基线占用时只会降速, 不能让分发器永远等待
+func TestResourceLimitsBelowBaseline(t *testing.T) {
+	tasks := make([]Task[int], 20)
+	for i := range tasks {
+		tasks[i] = Task[int]{URL: "http://example.com", Do: func(ctx context.Context) (int, error) {
+			time.Sleep(time.Millisecond)
+			return i, nil
+		}}
+	}
+	r := &Runner[int]{Config: Config{
+		Concurrency: 4,
+		Resources:   &ResourceLimits{MaxGoroutines: 1, MaxOpenFiles: 1, MaxMemory: 1},
+	}}
+
+	done := make(chan []Result[int])
+	go func() { done <- r.Run(tasks) }()
+	select {
+	case results := <-done:
+		for i, res := range results {
+			if res.Status != StatusSuccess || res.Value != i {
+				t.Errorf("#%d 状态 %s, 值 %d", i, res.Status, res.Value)
+			}
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("资源上限低于基线时运行未结束")
+	}
+	if r.Metrics().ResourceAdjusted == "" {
+		t.Error("上限低于基线时应记录放宽说明")
+	}
+}
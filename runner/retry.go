@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy 描述失败任务的重试策略, 零值表示不重试
+type RetryPolicy struct {
+	MaxAttempts int           // 最多执行次数(含首次), <=1 表示不重试
+	BaseDelay   time.Duration // 首次重试前的等待, 之后每次翻倍
+	MaxDelay    time.Duration // 单次等待上限, 0 表示不限
+	Jitter      float64       // 抖动比例(0~1): 实际等待在 [d*(1-Jitter), d] 内均匀随机
+
+	RetryIf func(err error) bool // 判断错误是否值得重试, 为 nil 时失败和超时都重试
+}
+
+// attempts 返回最多执行次数
+func (p RetryPolicy) attempts() int {
+	return max(p.MaxAttempts, 1)
+}
+
+// shouldRetry 判断第 attempt 次执行失败后是否继续重试
+func (p RetryPolicy) shouldRetry(attempt int, err error) bool {
+	if attempt >= p.attempts() {
+		return false
+	}
+	return p.RetryIf == nil || p.RetryIf(err)
+}
+
+// delay 返回第 attempt 次执行失败后、下一次重试前的等待时间
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 && d > 0 {
+		d -= time.Duration(rand.Float64() * min(p.Jitter, 1) * float64(d))
+	}
+	return d
+}
@@ -0,0 +1,647 @@
+// Package runner 并发执行一组任务, 并按提交顺序重组结果。
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 结果状态标识
+const (
+	StatusSuccess        = "成功"
+	StatusFailed         = "失败"
+	StatusCanceled       = "取消"
+	StatusTimeout        = "超时"
+	StatusThrottled      = "本地限流"
+	StatusShortCircuited = "断路"
+	StatusExhausted      = "本机资源耗尽" // 文件描述符或临时端口耗尽, 见 ExhaustionHint
+	StatusIncomplete     = "未完成"    // 整批截止时间(Config.Deadline)到达时尚未完成, 见 ErrDeadline
+)
+
+// Task 是一个待执行的任务, T 为任务返回的数据类型
+type Task[T any] struct {
+	URL   string                               // 任务标识(原始URL), 原样写入 Result
+	Do    func(ctx context.Context) (T, error) // 执行任务并返回数据
+	Retry *RetryPolicy                         // 该任务的重试策略, 为 nil 时使用 Config.Retry
+}
+
+// Result 是单个任务的执行结果
+type Result[T any] struct {
+	Value    T
+	Err      error
+	Index    int           // 请求顺序索引
+	URL      string        // 原始URL
+	Status   string        // 状态标识
+	Duration time.Duration // 从首次执行到最终结果的耗时(含重试等待)
+	Jitter   time.Duration // 启动前施加的随机抖动
+	Attempts int           // 实际执行次数, 1 表示未重试
+}
+
+// Metrics 是一次运行的内部指标
+type Metrics struct {
+	Workers         int           // 实际启动的 worker 数
+	FinalWorkers    int           // 运行结束时仍在工作的 worker 数(AutoReduce 可能使其小于 Workers)
+	Launched        int           // 实际交给 worker 启动的任务数
+	LaunchRate      float64       // 实际达到的启动速率(个/秒), 按首末两次启动的间隔计算
+	ChannelCap      int           // 结果通道容量
+	SendBlocked     int64         // 生产者在结果通道上阻塞的次数
+	SendBlockedTime time.Duration // 生产者累计阻塞时长
+
+	Hosts map[string]HostMetrics // 按主机的计数
+
+	ResourceWaits    int           // 因资源接近上限而暂停分发的次数
+	ResourceWaitTime time.Duration // 累计暂停时长
+	ResourceReason   string        // 最近一次暂停的原因
+	ResourceAdjusted string        // 上限低于运行开始时的基线占用而被放宽时的说明
+	ResourcePeak     ResourceUsage // 分发期间采样到的资源峰值(仅设置 Resources 时)
+
+	Winner int // RaceFirst 模式下率先成功的任务索引, 未启用或没有任务成功时为 -1
+}
+
+// Config 是 Runner 的运行配置, 零值即可使用
+type Config struct {
+	Concurrency int               // 同时执行的最大任务数, 0 表示每个任务一个协程
+	MaxPerHost  int               // 同一主机同时执行的最大任务数, 0 表示不限
+	Jitter      time.Duration     // 每个任务启动前的随机抖动窗口, 0 表示不抖动
+	Timeout     time.Duration     // 单个任务每次执行的超时时间, 0 表示不限
+	Retry       RetryPolicy       // 默认重试策略, 零值表示不重试
+	RateLimit   Limiter           // 任务启动的限流器, 为 nil 时不限流
+	Throttle    *AdaptiveThrottle // 按主机的客户端自适应限流, 为 nil 时不启用
+	Breaker     *CircuitBreaker   // 按主机的断路器, 为 nil 时不启用
+	Resources   *ResourceLimits   // 工具自身的资源保护上限, 为 nil 时不检查
+	Tracer      Tracer            // 追踪接入, 为 nil 时不追踪
+	Logger      *slog.Logger      // 结构化日志(task_start、retry、task_done、task_cancel 事件), 为 nil 时不记录
+	FailFast    bool              // 快速失败: 任一任务出错(失败、超时、断路或本地限流)即取消其余任务
+	RaceFirst   bool              // 竞速模式: 任一任务成功即取消其余任务, 胜者见 Metrics.Winner
+
+	// Deadline 大于 0 时限制整批运行的时长: 到期后不再启动新任务, 运行中的任务立即取消(不受 Drain 影响),
+	// 这些任务的状态为 StatusIncomplete
+	Deadline time.Duration
+
+	// Drain 大于 0 时, ctx 取消后只停止分发新任务, 运行中的任务最多再执行 Drain 时长才被取消;
+	// 为 0 时运行中的任务立即收到取消。快速失败与竞速触发的取消不受 Drain 影响
+	Drain time.Duration
+
+	Strict bool // 严格模式: 运行时校验 WaitGroup/通道不变量, 违反时 panic
+
+	// AutoReduce 为 true 时, 每遇到一次本机资源耗尽(StatusExhausted)就把 worker 数减半(至少保留 1 个)
+	AutoReduce bool
+}
+
+// Runner 保存运行配置与回调, 零值即可使用
+type Runner[T any] struct {
+	Config
+
+	OnResult  func(Result[T]) // 每收到一个结果时调用(按完成顺序)
+	OnOrdered func(Result[T]) // 结果按提交顺序就绪时调用
+
+	OnProgress func(Progress) // 每收到一个结果后调用, 传出进度快照
+
+	// Reporter 不为 nil 时接收开始、逐个结果与结束汇总事件, 见 TableReporter、JSONReporter
+	Reporter Reporter[T]
+
+	// Bus 不为 nil 时, 结果同时发布到 TopicResult 和 TopicOrdered;
+	// Run 返回前所有结果均已发布, 总线由调用方关闭
+	Bus *Bus[Result[T]]
+
+	mu      sync.Mutex
+	metrics Metrics
+}
+
+// ErrFailFast 表示快速失败模式下有任务出错, 运行被提前中止
+var ErrFailFast = errors.New("快速失败")
+
+// ErrRaceWon 是竞速模式下已有任务成功、其余任务被取消的原因
+var ErrRaceWon = errors.New("已有任务率先成功")
+
+// ErrNoWinner 表示竞速模式下没有任何任务成功
+var ErrNoWinner = errors.New("竞速模式下没有任务成功")
+
+// ErrDeadline 是整批截止时间到达后未完成任务的错误
+var ErrDeadline = errors.New("整批截止时间已到")
+
+// Run 使用默认配置并发执行 tasks, 返回按提交顺序排列的结果
+func Run[T any](tasks []Task[T]) []Result[T] {
+	var r Runner[T]
+	return r.Run(tasks)
+}
+
+// RunWithContext 同 Run, 但 ctx 取消后不再启动新任务, 并通知运行中的任务中止
+func RunWithContext[T any](ctx context.Context, tasks []Task[T]) ([]Result[T], error) {
+	var r Runner[T]
+	return r.RunWithContext(ctx, tasks)
+}
+
+// Metrics 返回最近一次 Run 的内部指标
+func (r *Runner[T]) Metrics() Metrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.metrics
+}
+
+// Run 并发执行 tasks(并发度见 Concurrency), 返回按提交顺序排列的结果
+func (r *Runner[T]) Run(tasks []Task[T]) []Result[T] {
+	results, _ := r.RunWithContext(context.Background(), tasks)
+	return results
+}
+
+// RunWithContext 同 Run, 但受 ctx 控制: ctx 取消后不再启动新任务,
+// 运行中的任务通过传入的 ctx 得到通知(设置 Drain 时延后通知), 这些任务的结果状态为 StatusCanceled。
+// 快速失败模式被触发时, 返回包装了 ErrFailFast 和所有任务错误的组合错误, 以及部分结果
+func (r *Runner[T]) RunWithContext(ctx context.Context, tasks []Task[T]) ([]Result[T], error) {
+	runStart := time.Now()
+	endRun := func(error) {}
+	if r.Tracer != nil {
+		ctx, endRun = r.Tracer.StartRun(ctx, len(tasks))
+	}
+	if r.Reporter != nil {
+		r.Reporter.OnStart(len(tasks))
+	}
+	if r.Deadline > 0 {
+		var cancelDeadline context.CancelFunc
+		ctx, cancelDeadline = context.WithTimeoutCause(ctx, r.Deadline, fmt.Errorf("%w (%v)", ErrDeadline, r.Deadline))
+		defer cancelDeadline()
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	var failedFast atomic.Bool
+	var winner atomic.Int64
+	winner.Store(-1)
+
+	// taskCtx 传给运行中的任务; 设置 Drain 时它比 ctx 晚取消, 让已开始的任务有机会完成
+	taskCtx := ctx
+	if r.Drain > 0 {
+		var cancelTasks context.CancelCauseFunc
+		taskCtx, cancelTasks = context.WithCancelCause(context.WithoutCancel(ctx))
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			defer cancelTasks(nil)
+			select {
+			case <-ctx.Done():
+			case <-done:
+				return
+			}
+			if cause := context.Cause(ctx); !failedFast.Load() && !errors.Is(cause, ErrDeadline) && !errors.Is(cause, ErrRaceWon) {
+				select {
+				case <-time.After(r.Drain):
+				case <-done:
+					return
+				}
+			}
+			cancelTasks(context.Cause(ctx))
+		}()
+	}
+
+	// 1. 确定 worker 数: Concurrency>0 时为固定数量, 否则每个任务一个协程
+	workers := len(tasks)
+	if r.Concurrency > 0 && r.Concurrency < workers {
+		workers = r.Concurrency
+	}
+	if r.Resources != nil {
+		workers = r.Resources.workers(workers)
+	}
+
+	// 2. 创建带缓冲的结果通道: 容量与 worker 数相同, 内存随并发度而非任务数增长;
+	// 聚合端跟不上时生产者会短暂阻塞, 计入 sendStats
+	resultChan := make(chan Result[T], workers)
+	var sends sendStats
+	strict := newStrictChecker(r.Strict, len(tasks))
+
+	var wg sync.WaitGroup
+	deliver := func(result Result[T]) {
+		// 整批截止时间到达而被取消的任务记为未完成
+		if result.Status == StatusCanceled && errors.Is(context.Cause(ctx), ErrDeadline) {
+			result.Status, result.Err = StatusIncomplete, context.Cause(ctx)
+		}
+		// 竞速落败而被取消的任务直接报告取消原因, 而非底层的 context canceled
+		if result.Status == StatusCanceled && errors.Is(context.Cause(ctx), ErrRaceWon) {
+			result.Err = context.Cause(ctx)
+		}
+		// 快速失败: 第一个出错的任务取消其余所有任务
+		if r.FailFast && isError(result.Status) && failedFast.CompareAndSwap(false, true) {
+			cancel(fmt.Errorf("%w (由 #%d 触发): %w", ErrFailFast, result.Index, result.Err))
+		}
+		// 竞速: 第一个成功的任务取消其余所有任务
+		if r.RaceFirst && result.Status == StatusSuccess && winner.CompareAndSwap(-1, int64(result.Index)) {
+			cancel(fmt.Errorf("%w (#%d)", ErrRaceWon, result.Index))
+		}
+		logResult(ctx, &r.Config, result)
+		strict.beforeSend(result.Index, result.URL)
+		send(&sends, resultChan, result)
+	}
+
+	queue := make(chan int)
+	gate := newHostGate(r.MaxPerHost, tasks)
+	var running atomic.Int64 // 已交给 worker 尚未完成的任务数, 供进度快照与资源保护使用
+	var active atomic.Int64  // 允许工作的 worker 数, 编号不小于它的 worker 完成手头任务后退出
+	active.Store(int64(workers))
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				result := r.executeTraced(taskCtx, i, tasks[i])
+				running.Add(-1)
+				gate.release(i)
+				if r.AutoReduce && result.Status == StatusExhausted {
+					halveWorkers(&active)
+				}
+				deliver(result)
+				if int64(w) >= active.Load() {
+					return
+				}
+			}
+		}()
+	}
+
+	// 分发任务(携带索引序号), 在后台进行以便聚合端立即开始接收结果
+	var launched int
+	var firstLaunch, lastLaunch time.Time
+	guard := &resourceGuard{limits: r.Resources, running: &running}
+	launch := func(i int) {
+		task := tasks[i]
+
+		// 已取消: 不再启动新任务, 直接记为取消
+		if ctx.Err() != nil {
+			gate.release(i)
+			deliver(Result[T]{Index: i, URL: task.URL, Status: StatusCanceled, Err: context.Cause(ctx)})
+			return
+		}
+
+		// 资源保护: 接近上限时暂停分发, 等在途任务释放资源
+		if err := guard.wait(ctx); err != nil {
+			gate.release(i)
+			deliver(Result[T]{Index: i, URL: task.URL, Status: StatusCanceled, Err: err})
+			return
+		}
+
+		// 限流: 等待允许启动
+		if r.RateLimit != nil {
+			if err := r.RateLimit.Wait(ctx); err != nil {
+				gate.release(i)
+				deliver(Result[T]{Index: i, URL: task.URL, Status: StatusCanceled, Err: err})
+				return
+			}
+		}
+
+		running.Add(1)
+		select {
+		case queue <- i:
+			lastLaunch = time.Now()
+			if launched == 0 {
+				firstLaunch = lastLaunch
+			}
+			launched++
+		case <-ctx.Done():
+			running.Add(-1)
+			gate.release(i)
+			deliver(Result[T]{Index: i, URL: task.URL, Status: StatusCanceled, Err: context.Cause(ctx)})
+		}
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(queue)
+
+		// 主机已满的任务被挂起, 不阻塞其他主机的任务; 同主机任务完成后经 gate.ready 补发
+		deferred := 0
+		for i := range tasks {
+			strict.submit()
+			for drained := false; !drained; {
+				select {
+				case j := <-gate.ready:
+					deferred--
+					launch(j)
+				default:
+					drained = true
+				}
+			}
+			if !gate.acquire(i) {
+				deferred++
+				continue
+			}
+			launch(i)
+		}
+		for ; deferred > 0; deferred-- {
+			launch(<-gate.ready)
+		}
+	}()
+
+	// 3. 后台等待所有任务完成后关闭通道
+	aggregateDone := make(chan struct{})
+	go func() {
+		defer close(aggregateDone)
+		wg.Wait()
+		strict.markClosed()
+		close(resultChan) // 确保所有结果已发送
+	}()
+
+	// 4. 按完成顺序接收结果, 并用索引重组为提交顺序
+	results := make([]Result[T], len(tasks))
+	ordered := OrderedCollector[T]{Total: len(tasks)}
+
+	start := time.Now()
+	var completed, errCount int
+	for result := range resultChan {
+		strict.receive(result.Index, result.URL)
+		if r.OnResult != nil {
+			r.OnResult(result)
+		}
+		if r.Reporter != nil {
+			r.Reporter.OnResult(result)
+		}
+		completed++
+		if isError(result.Status) {
+			errCount++
+		}
+		if r.OnProgress != nil {
+			r.OnProgress(newProgress(completed, len(tasks), int(running.Load()), errCount, time.Since(start)))
+		}
+		if r.Bus != nil {
+			r.Bus.Publish(TopicResult, result)
+		}
+
+		for _, res := range ordered.Add(result) {
+			results[res.Index] = res
+			if r.OnOrdered != nil {
+				r.OnOrdered(res)
+			}
+			if r.Bus != nil {
+				r.Bus.Publish(TopicOrdered, res)
+			}
+		}
+	}
+
+	// 5. 确保所有结果都按顺序处理
+	strict.finish(ordered.Pending())
+	<-aggregateDone
+
+	var launchRate float64
+	if span := lastLaunch.Sub(firstLaunch); launched > 1 && span > 0 {
+		launchRate = float64(launched-1) / span.Seconds()
+	}
+
+	metrics := Metrics{
+		Workers:         workers,
+		FinalWorkers:    int(active.Load()),
+		Launched:        launched,
+		LaunchRate:      launchRate,
+		ChannelCap:      cap(resultChan),
+		SendBlocked:     sends.blocked.Load(),
+		SendBlockedTime: time.Duration(sends.blockedTime.Load()),
+		Hosts:           gate.metrics(),
+
+		ResourceWaits:    guard.waits,
+		ResourceWaitTime: guard.waitTime,
+		ResourceReason:   guard.reason,
+		ResourceAdjusted: guard.adjusted,
+		ResourcePeak:     guard.peak,
+
+		Winner: int(winner.Load()),
+	}
+	r.mu.Lock()
+	r.metrics = metrics
+	r.mu.Unlock()
+
+	var err error
+	if failedFast.Load() {
+		var errs []error
+		for _, res := range results {
+			if isError(res.Status) {
+				errs = append(errs, res.Err)
+			}
+		}
+		err = fmt.Errorf("%w: %w", ErrFailFast, errors.Join(errs...))
+	}
+	if r.RaceFirst && winner.Load() < 0 {
+		var errs []error
+		for _, res := range results {
+			if res.Err != nil {
+				errs = append(errs, res.Err)
+			}
+		}
+		err = errors.Join(err, fmt.Errorf("%w: %w", ErrNoWinner, errors.Join(errs...)))
+	}
+	// 重复或越界的结果索引说明结果可能错位, 作为整次运行的错误返回
+	if cerr := ordered.Err(); cerr != nil {
+		err = errors.Join(err, cerr)
+	}
+	if r.Reporter != nil {
+		r.Reporter.OnFinish(Summary[T]{
+			Results: results,
+			Stats:   ComputeStats(results),
+			Metrics: metrics,
+			Elapsed: time.Since(runStart),
+			Err:     err,
+		})
+	}
+	endRun(err)
+	return results, err
+}
+
+// executeTraced 执行单个任务, 设置了 Tracer 时为其创建 span
+func (r *Runner[T]) executeTraced(ctx context.Context, index int, task Task[T]) Result[T] {
+	if r.Tracer == nil {
+		return execute(ctx, &r.Config, index, task)
+	}
+	ctx, end := r.Tracer.StartTask(ctx, index, task.URL)
+	result := execute(ctx, &r.Config, index, task)
+	end(result.Attempts, result.Status, result.Err)
+	return result
+}
+
+// halveWorkers 把允许工作的 worker 数减半, 至少保留 1 个
+func halveWorkers(active *atomic.Int64) {
+	for {
+		n := active.Load()
+		if n <= 1 || active.CompareAndSwap(n, n/2) {
+			return
+		}
+	}
+}
+
+// failed 判断一次尝试是否由目标出错导致(取消不算), 供重试与断路器判断
+func failed(status string) bool {
+	return status == StatusFailed || status == StatusTimeout || status == StatusExhausted
+}
+
+// isError 判断结果是否出错, 包括被断路或本地限流拒绝的结果(取消、未完成不算), 供快速失败与进度统计使用
+func isError(status string) bool {
+	return failed(status) || status == StatusShortCircuited || status == StatusThrottled
+}
+
+// execute 执行单个任务并填充计时与状态
+func execute[T any](ctx context.Context, cfg *Config, index int, task Task[T]) Result[T] {
+	result := Result[T]{
+		Index:  index,
+		URL:    task.URL,
+		Jitter: startJitter(cfg.Jitter),
+	}
+
+	if err := sleepContext(ctx, result.Jitter); err != nil {
+		result.Status = StatusCanceled
+		result.Err = err
+		return result
+	}
+
+	policy := cfg.Retry
+	if task.Retry != nil {
+		policy = *task.Retry
+	}
+
+	cfg.logEvent(ctx, slog.LevelDebug, "task_start", slog.Int("index", index), slog.String("url", task.URL))
+	host := hostOf(task.URL)
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		// 断路器: 主机连续出错后在冷却期内直接短路, 不再重试; 本次尝试未发出, 不计入 Attempts
+		probe := false
+		if cfg.Breaker != nil {
+			var ok bool
+			if ok, probe = cfg.Breaker.allow(host, time.Now()); !ok {
+				result.Status, result.Err = StatusShortCircuited, withPrevious(ErrCircuitOpen, result.Err)
+				break
+			}
+		}
+
+		// 自适应限流: 后端近期拒绝过多时直接在本地拒绝, 不再重试; 同样不计入 Attempts
+		if cfg.Throttle != nil && !cfg.Throttle.allow(host, time.Now()) {
+			if cfg.Breaker != nil {
+				cfg.Breaker.record(host, StatusThrottled, ErrThrottled, probe, time.Now())
+			}
+			result.Status, result.Err = StatusThrottled, withPrevious(ErrThrottled, result.Err)
+			break
+		}
+
+		result.Attempts = attempt
+		result.Value, result.Status, result.Err = attemptOnce(ctx, cfg.Timeout, task)
+		if cfg.Throttle != nil && serverAccepted(result.Status, result.Err) {
+			cfg.Throttle.accepted(host, time.Now())
+		}
+		if cfg.Breaker != nil {
+			cfg.Breaker.record(host, result.Status, result.Err, probe, time.Now())
+		}
+
+		if !failed(result.Status) {
+			break
+		}
+		if !policy.shouldRetry(attempt, result.Err) {
+			break
+		}
+		delay := policy.delay(attempt)
+		cfg.logEvent(ctx, slog.LevelInfo, "retry",
+			slog.Int("index", index), slog.String("url", task.URL), slog.Int("attempt", attempt),
+			slog.String("status", result.Status), slog.Duration("delay", delay), slog.Any("error", result.Err))
+		if err := sleepContext(ctx, delay); err != nil {
+			result.Status = StatusCanceled
+			result.Err = err
+			break
+		}
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// withPrevious 在重试被本地拒绝时保留上一次尝试的真实错误, 两者都可用 errors.Is 判断
+func withPrevious(local, prev error) error {
+	if prev == nil {
+		return local
+	}
+	return fmt.Errorf("%w (上一次尝试: %w)", local, prev)
+}
+
+// attemptOnce 执行一次任务并判定状态
+func attemptOnce[T any](ctx context.Context, timeout time.Duration, task Task[T]) (T, string, error) {
+	taskCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout > 0 {
+		taskCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	start := time.Now()
+	value, err := call(taskCtx, task)
+
+	switch {
+	case err == nil:
+		return value, StatusSuccess, nil
+	case ctx.Err() != nil, errors.Is(err, context.Canceled):
+		return value, StatusCanceled, err
+	case taskCtx.Err() == context.DeadlineExceeded:
+		return value, StatusTimeout, fmt.Errorf("任务超时 (限制 %v, 耗时 %v): %w", timeout, time.Since(start), err)
+	case exhausted(err):
+		return value, StatusExhausted, err
+	default:
+		return value, StatusFailed, err
+	}
+}
+
+// call 执行 task.Do; ctx 结束时立即返回, 不等待不响应取消的任务(其协程在后台运行至返回)
+func call[T any](ctx context.Context, task Task[T]) (T, error) {
+	type outcome struct {
+		value T
+		err   error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		v, err := task.Do(ctx)
+		done <- outcome{v, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.value, o.err
+	case <-ctx.Done():
+		var zero T
+		return zero, context.Cause(ctx)
+	}
+}
+
+// sendStats 记录生产者向结果通道发送时的阻塞情况
+type sendStats struct {
+	blocked     atomic.Int64 // 阻塞次数
+	blockedTime atomic.Int64 // 累计阻塞时长(纳秒)
+}
+
+// send 优先非阻塞发送, 通道已满时才等待并记录阻塞
+func send[T any](s *sendStats, ch chan<- Result[T], r Result[T]) {
+	select {
+	case ch <- r:
+		return
+	default:
+	}
+
+	start := time.Now()
+	ch <- r
+	s.blocked.Add(1)
+	s.blockedTime.Add(int64(time.Since(start)))
+}
+
+// startJitter 在 [0, window) 内均匀取一个启动延迟, window<=0 时不抖动
+func startJitter(window time.Duration) time.Duration {
+	if window <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(window)))
+}
+
+// sleepContext 等待 d, 期间可被 ctx 取消
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-timer.C:
+		return nil
+	}
+}
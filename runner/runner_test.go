@@ -0,0 +1,253 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockUntilDone 是一直运行到被取消的任务
+func blockUntilDone(ctx context.Context) (int, error) {
+	<-ctx.Done()
+	return 0, ctx.Err()
+}
+
+// sleepThen 返回运行 d 后以 err 结束的任务, 期间被取消则提前返回
+func sleepThen(d time.Duration, err error) func(context.Context) (int, error) {
+	return func(ctx context.Context) (int, error) {
+		select {
+		case <-time.After(d):
+			return 1, err
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+func TestRunnerCancellation(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   Config
+		tasks []func(context.Context) (int, error)
+		ctx   func() (context.Context, context.CancelFunc) // 为 nil 时使用 context.Background
+		check func(t *testing.T, r *Runner[int], results []Result[int], err error)
+	}{
+		{
+			name: "快速失败: 首个错误取消其余任务",
+			cfg:  Config{FailFast: true},
+			tasks: []func(context.Context) (int, error){
+				blockUntilDone,
+				sleepThen(10*time.Millisecond, errors.New("boom")),
+				blockUntilDone,
+			},
+			check: func(t *testing.T, _ *Runner[int], results []Result[int], err error) {
+				if !errors.Is(err, ErrFailFast) {
+					t.Fatalf("err = %v, 期望 ErrFailFast", err)
+				}
+				if results[1].Status != StatusFailed {
+					t.Errorf("#1 状态 %s, 期望 %s", results[1].Status, StatusFailed)
+				}
+				for _, i := range []int{0, 2} {
+					if results[i].Status != StatusCanceled || !errors.Is(results[i].Err, ErrFailFast) {
+						t.Errorf("#%d 状态 %s, 错误 %v, 期望因快速失败取消", i, results[i].Status, results[i].Err)
+					}
+				}
+			},
+		},
+		{
+			name: "竞速: 首个成功者胜出, 其余以 ErrRaceWon 取消",
+			cfg:  Config{RaceFirst: true},
+			tasks: []func(context.Context) (int, error){
+				blockUntilDone,
+				blockUntilDone,
+				sleepThen(10*time.Millisecond, nil),
+			},
+			check: func(t *testing.T, r *Runner[int], results []Result[int], err error) {
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				if w := r.Metrics().Winner; w != 2 {
+					t.Fatalf("Winner = %d, 期望 2", w)
+				}
+				for _, i := range []int{0, 1} {
+					if results[i].Status != StatusCanceled || !errors.Is(results[i].Err, ErrRaceWon) {
+						t.Errorf("#%d 状态 %s, 错误 %v, 期望 ErrRaceWon", i, results[i].Status, results[i].Err)
+					}
+				}
+			},
+		},
+		{
+			name: "竞速: 没有成功者时返回 ErrNoWinner",
+			cfg:  Config{RaceFirst: true},
+			tasks: []func(context.Context) (int, error){
+				sleepThen(0, errors.New("a")),
+				sleepThen(0, errors.New("b")),
+			},
+			check: func(t *testing.T, r *Runner[int], _ []Result[int], err error) {
+				if !errors.Is(err, ErrNoWinner) || r.Metrics().Winner != -1 {
+					t.Fatalf("err = %v, Winner = %d, 期望 ErrNoWinner 与 -1", err, r.Metrics().Winner)
+				}
+			},
+		},
+		{
+			name: "整批截止时间: 未完成的任务标记为未完成",
+			cfg:  Config{Deadline: 50 * time.Millisecond, Drain: time.Hour},
+			tasks: []func(context.Context) (int, error){
+				sleepThen(0, nil),
+				blockUntilDone,
+				blockUntilDone,
+			},
+			check: func(t *testing.T, _ *Runner[int], results []Result[int], err error) {
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				if results[0].Status != StatusSuccess {
+					t.Errorf("#0 状态 %s, 期望成功", results[0].Status)
+				}
+				for _, i := range []int{1, 2} {
+					if results[i].Status != StatusIncomplete || !errors.Is(results[i].Err, ErrDeadline) {
+						t.Errorf("#%d 状态 %s, 错误 %v, 期望未完成", i, results[i].Status, results[i].Err)
+					}
+				}
+			},
+		},
+		{
+			name: "收尾: ctx 取消后运行中的任务仍可完成",
+			cfg:  Config{Drain: 5 * time.Second},
+			tasks: []func(context.Context) (int, error){
+				sleepThen(100*time.Millisecond, nil),
+				sleepThen(100*time.Millisecond, nil),
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			check: func(t *testing.T, _ *Runner[int], results []Result[int], err error) {
+				for i, res := range results {
+					if res.Status != StatusSuccess {
+						t.Errorf("#%d 状态 %s, 错误 %v, 期望在收尾期内完成", i, res.Status, res.Err)
+					}
+				}
+			},
+		},
+		{
+			name: "不设收尾: ctx 取消立即取消运行中的任务",
+			tasks: []func(context.Context) (int, error){
+				sleepThen(time.Hour, nil),
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			check: func(t *testing.T, _ *Runner[int], results []Result[int], err error) {
+				if results[0].Status != StatusCanceled {
+					t.Errorf("#0 状态 %s, 期望取消", results[0].Status)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.Background(), context.CancelFunc(func() {})
+			if tt.ctx != nil {
+				ctx, cancel = tt.ctx()
+			}
+			defer cancel()
+
+			tasks := make([]Task[int], len(tt.tasks))
+			for i, do := range tt.tasks {
+				tasks[i] = Task[int]{URL: fmt.Sprintf("http://host/%d", i), Do: do}
+			}
+			r := &Runner[int]{Config: tt.cfg}
+
+			type outcome struct {
+				results []Result[int]
+				err     error
+			}
+			done := make(chan outcome)
+			go func() {
+				results, err := r.RunWithContext(ctx, tasks)
+				done <- outcome{results, err}
+			}()
+			select {
+			case o := <-done:
+				tt.check(t, r, o.results, o.err)
+			case <-time.After(10 * time.Second):
+				t.Fatal("运行未结束")
+			}
+		})
+	}
+}
+
+// 同一主机的并发数不超过 MaxPerHost, 被推迟的任务最终全部执行
+func TestRunnerMaxPerHost(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		maxPerHost  int
+		hosts       []string
+		perHost     int
+	}{
+		{"单主机", 0, 2, []string{"a"}, 20},
+		{"多主机交错", 0, 3, []string{"a", "b", "c"}, 15},
+		{"工作池小于主机上限", 2, 3, []string{"a", "b"}, 10},
+		{"上限为 1 时串行", 8, 1, []string{"a", "b"}, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			current := make(map[string]int)
+			peak := make(map[string]int)
+			var ran atomic.Int64
+
+			var tasks []Task[int]
+			for i := 0; i < tt.perHost; i++ {
+				for _, host := range tt.hosts {
+					tasks = append(tasks, Task[int]{
+						URL: "http://" + host + "/" + fmt.Sprint(i),
+						Do: func(ctx context.Context) (int, error) {
+							mu.Lock()
+							current[host]++
+							peak[host] = max(peak[host], current[host])
+							mu.Unlock()
+							time.Sleep(2 * time.Millisecond)
+							mu.Lock()
+							current[host]--
+							mu.Unlock()
+							ran.Add(1)
+							return 0, nil
+						},
+					})
+				}
+			}
+
+			r := &Runner[int]{Config: Config{Concurrency: tt.concurrency, MaxPerHost: tt.maxPerHost}}
+			results := r.Run(tasks)
+
+			if got := int(ran.Load()); got != len(tasks) {
+				t.Fatalf("执行了 %d 个任务, 期望 %d 个", got, len(tasks))
+			}
+			for i, res := range results {
+				if res.Index != i || res.Status != StatusSuccess {
+					t.Fatalf("#%d: Index %d 状态 %s", i, res.Index, res.Status)
+				}
+			}
+			for _, host := range tt.hosts {
+				if peak[host] > tt.maxPerHost {
+					t.Errorf("主机 %s 最大并发 %d, 超过上限 %d", host, peak[host], tt.maxPerHost)
+				}
+				h := r.Metrics().Hosts[host]
+				if h.Tasks != tt.perHost || h.Peak > tt.maxPerHost {
+					t.Errorf("主机 %s 指标 %+v, 期望任务数 %d、峰值不超过 %d", host, h, tt.perHost, tt.maxPerHost)
+				}
+			}
+		})
+	}
+}
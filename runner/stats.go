@@ -0,0 +1,76 @@
+package runner
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Stats 是一批结果的耗时统计
+type Stats struct {
+	Count  int // 参与统计的结果数(未开始执行即被取消、被断路或本地限流拒绝的任务不计入)
+	Min    time.Duration
+	Max    time.Duration
+	Mean   time.Duration
+	StdDev time.Duration // 总体标准差
+	P50    time.Duration
+	P90    time.Duration
+	P95    time.Duration
+	P99    time.Duration
+}
+
+// Measured 报告 r 的耗时是否反映了真实请求: 至少发出过一次, 且最终没有被本地拒绝(断路、限流)
+func (r Result[T]) Measured() bool {
+	return r.Attempts > 0 && r.Status != StatusShortCircuited && r.Status != StatusThrottled
+}
+
+// ComputeStats 统计 results 中 Measured 的结果的耗时(Result.Duration, 含重试)分布
+func ComputeStats[T any](results []Result[T]) Stats {
+	durations := make([]time.Duration, 0, len(results))
+	for _, r := range results {
+		if r.Measured() {
+			durations = append(durations, r.Duration)
+		}
+	}
+	if len(durations) == 0 {
+		return Stats{}
+	}
+	slices.Sort(durations)
+
+	var sum float64
+	for _, d := range durations {
+		sum += float64(d)
+	}
+	mean := sum / float64(len(durations))
+	var variance float64
+	for _, d := range durations {
+		variance += (float64(d) - mean) * (float64(d) - mean)
+	}
+	variance /= float64(len(durations))
+
+	return Stats{
+		Count:  len(durations),
+		Min:    durations[0],
+		Max:    durations[len(durations)-1],
+		Mean:   time.Duration(mean),
+		StdDev: time.Duration(math.Sqrt(variance)),
+		P50:    Quantile(durations, 0.50),
+		P90:    Quantile(durations, 0.90),
+		P95:    Quantile(durations, 0.95),
+		P99:    Quantile(durations, 0.99),
+	}
+}
+
+// Quantile 对已排序的耗时做线性插值取分位数(q 取 0~1)
+func Quantile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := q * float64(len(sorted)-1)
+	lo := int(pos)
+	if lo+1 >= len(sorted) {
+		return sorted[lo]
+	}
+	frac := pos - float64(lo)
+	return sorted[lo] + time.Duration(frac*float64(sorted[lo+1]-sorted[lo]))
+}
@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// strictChecker 在严格模式下校验 WaitGroup/通道用法的内部不变量, 违反时带诊断信息 panic
+type strictChecker struct {
+	enabled   bool
+	submitted atomic.Int64
+	received  atomic.Int64
+	closed    atomic.Bool
+	seen      []bool // 仅由聚合协程访问
+}
+
+func newStrictChecker(enabled bool, total int) *strictChecker {
+	return &strictChecker{enabled: enabled, seen: make([]bool, total)}
+}
+
+func (c *strictChecker) failf(format string, args ...any) {
+	panic(fmt.Sprintf("严格模式: "+format+" (已提交 %d, 已接收 %d, 通道已关闭 %v)",
+		append(args, c.submitted.Load(), c.received.Load(), c.closed.Load())...))
+}
+
+// submit 记录一次任务提交(分发器每处理一个任务调用一次)
+func (c *strictChecker) submit() {
+	c.submitted.Add(1)
+}
+
+// beforeSend 确保结果通道关闭后不再有发送
+func (c *strictChecker) beforeSend(index int, url string) {
+	if c.enabled && c.closed.Load() {
+		c.failf("结果通道关闭后仍有发送 [#%d %s]", index, url)
+	}
+}
+
+// markClosed 在关闭结果通道前调用
+func (c *strictChecker) markClosed() {
+	c.closed.Store(true)
+}
+
+// receive 校验收到的结果索引合法且不重复
+func (c *strictChecker) receive(index int, url string) {
+	c.received.Add(1)
+	if !c.enabled {
+		return
+	}
+	if index < 0 || index >= len(c.seen) {
+		c.failf("结果索引越界 [#%d %s]", index, url)
+	}
+	if c.seen[index] {
+		c.failf("重复的结果索引 [#%d %s]", index, url)
+	}
+	c.seen[index] = true
+}
+
+// finish 在聚合循环因结果通道关闭而退出后、等待 aggregateDone 之前调用:
+// 此时通道必须是由关闭协程在所有发送完成后关闭的
+func (c *strictChecker) finish(pending int) {
+	if !c.enabled {
+		return
+	}
+	if !c.closed.Load() {
+		c.failf("结果通道在关闭协程标记前已被关闭, 聚合提前退出")
+	}
+	if c.received.Load() != c.submitted.Load() {
+		c.failf("接收结果数与提交任务数不一致")
+	}
+	if pending != 0 {
+		c.failf("仍有 %d 个结果未按顺序输出", pending)
+	}
+}
@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrMaxRestarts 表示被监督的协程重启次数已耗尽
+var ErrMaxRestarts = errors.New("超过最大重启次数")
+
+// Supervisor 监督一个长期运行的协程: 返回错误或 panic 后按指数退避重启,
+// 重启次数耗尽时把最后一次错误升级给调用方。零值即可使用(立即重启, 不限次数)
+type Supervisor struct {
+	Name        string        // 名称, 用于错误信息
+	MaxRestarts int           // 最大重启次数, 0 表示不限
+	Backoff     time.Duration // 首次重启前的等待, 之后每次翻倍
+	MaxBackoff  time.Duration // 退避上限, 0 表示不设上限
+
+	OnRestart func(attempt int, err error) // 每次重启前调用
+}
+
+// Run 运行 fn 直到其返回 nil 或 ctx 取消; fn 返回错误或 panic 时按退避重启。
+// 重启次数耗尽时返回包装了 ErrMaxRestarts 和最后一次错误的错误
+func (s *Supervisor) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	backoff := s.Backoff
+	for restarts := 0; ; restarts++ {
+		err := s.runOnce(ctx, fn)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if s.MaxRestarts > 0 && restarts >= s.MaxRestarts {
+			return fmt.Errorf("%s: %w (%d 次): %w", s.Name, ErrMaxRestarts, restarts, err)
+		}
+
+		if s.OnRestart != nil {
+			s.OnRestart(restarts+1, err)
+		}
+		if err := sleepContext(ctx, backoff); err != nil {
+			return err
+		}
+		backoff *= 2
+		if s.MaxBackoff > 0 && backoff > s.MaxBackoff {
+			backoff = s.MaxBackoff
+		}
+	}
+}
+
+// runOnce 运行一次 fn, 把 panic 转换为错误
+func (s *Supervisor) runOnce(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("%s: panic: %v", s.Name, v)
+		}
+	}()
+	return fn(ctx)
+}
@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 出错和 panic 都会重启, 退避逐次翻倍且不超过上限, 成功返回后停止
+func TestSupervisorRestart(t *testing.T) {
+	var restarts []int
+	s := &Supervisor{
+		Name:       "worker",
+		Backoff:    time.Millisecond,
+		MaxBackoff: 2 * time.Millisecond,
+		OnRestart:  func(attempt int, err error) { restarts = append(restarts, attempt) },
+	}
+	runs := 0
+	err := s.Run(context.Background(), func(ctx context.Context) error {
+		switch runs++; runs {
+		case 1:
+			return errors.New("boom")
+		case 2:
+			panic("崩溃")
+		default:
+			return nil
+		}
+	})
+	if err != nil || runs != 3 {
+		t.Fatalf("err = %v, 运行 %d 次, 期望第 3 次成功", err, runs)
+	}
+	if len(restarts) != 2 || restarts[0] != 1 || restarts[1] != 2 {
+		t.Errorf("重启回调 %v, 期望 [1 2]", restarts)
+	}
+}
+
+// 重启次数耗尽时升级最后一次错误; ctx 取消时停止重启
+func TestSupervisorGiveUp(t *testing.T) {
+	boom := errors.New("boom")
+	s := &Supervisor{Name: "worker", MaxRestarts: 2}
+	runs := 0
+	err := s.Run(context.Background(), func(ctx context.Context) error {
+		runs++
+		return boom
+	})
+	if !errors.Is(err, ErrMaxRestarts) || !errors.Is(err, boom) || !strings.Contains(err.Error(), "worker") {
+		t.Errorf("err = %v, 期望包装 ErrMaxRestarts 与最后一次错误", err)
+	}
+	if runs != 3 {
+		t.Errorf("运行 %d 次, 期望首次加 2 次重启", runs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s = &Supervisor{Backoff: time.Hour}
+	err = s.Run(ctx, func(ctx context.Context) error {
+		cancel()
+		return boom
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, 期望 ctx 取消", err)
+	}
+}
@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrThrottled 表示请求被客户端自适应限流在本地拒绝, 未发往服务端
+var ErrThrottled = errors.New("本地自适应限流")
+
+const throttleBuckets = 10
+
+// AdaptiveThrottle 实现 Google SRE 的客户端自适应限流: 按主机统计窗口内的
+// 请求数 requests 与被接受数 accepts, 以 max(0, (requests-K*accepts)/(requests+1))
+// 的概率在本地直接拒绝请求, 使后端过载时客户端自动减压
+type AdaptiveThrottle struct {
+	K      float64       // 倍数, 越小越激进, 通常取 2
+	Window time.Duration // 统计窗口, 为 0 时取 2 分钟
+
+	mu    sync.Mutex
+	hosts map[string]*throttleWindow
+}
+
+// NewAdaptiveThrottle 创建倍数为 k、统计窗口 2 分钟的自适应限流器
+func NewAdaptiveThrottle(k float64) *AdaptiveThrottle {
+	return &AdaptiveThrottle{K: k}
+}
+
+// throttleWindow 是按时间分桶的滑动计数
+type throttleWindow struct {
+	buckets [throttleBuckets]struct {
+		slot              int64
+		requests, accepts float64
+	}
+}
+
+func (t *AdaptiveThrottle) window(host string) *throttleWindow {
+	if t.hosts == nil {
+		t.hosts = make(map[string]*throttleWindow)
+	}
+	w, ok := t.hosts[host]
+	if !ok {
+		w = &throttleWindow{}
+		t.hosts[host] = w
+	}
+	return w
+}
+
+func (t *AdaptiveThrottle) bucketWidth() time.Duration {
+	window := t.Window
+	if window <= 0 {
+		window = 2 * time.Minute
+	}
+	return window / throttleBuckets
+}
+
+// allow 记录一次请求并按拒绝概率决定是否放行
+func (t *AdaptiveThrottle) allow(host string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	slot := now.UnixNano() / int64(t.bucketWidth())
+	w := t.window(host)
+
+	var requests, accepts float64
+	for _, b := range w.buckets {
+		if slot-b.slot < throttleBuckets {
+			requests += b.requests
+			accepts += b.accepts
+		}
+	}
+	w.add(slot, 1, 0)
+
+	p := max(0, (requests-t.K*accepts)/(requests+1))
+	return rand.Float64() >= p
+}
+
+// serverAccepted 判断一次尝试是否被服务端受理: 成功, 或服务端以非过载状态码作答(如 404、401);
+// 超时、连接错误、429/503 以及无法判断来源的错误都视为拒绝
+func serverAccepted(status string, err error) bool {
+	if status == StatusSuccess {
+		return true
+	}
+	var se *StatusError
+	return status == StatusFailed && errors.As(err, &se) && !se.Overloaded()
+}
+
+// accepted 记录一次被服务端接受的请求
+func (t *AdaptiveThrottle) accepted(host string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.window(host).add(now.UnixNano()/int64(t.bucketWidth()), 0, 1)
+}
+
+func (w *throttleWindow) add(slot int64, requests, accepts float64) {
+	b := &w.buckets[slot%throttleBuckets]
+	if b.slot != slot {
+		b.slot, b.requests, b.accepts = slot, 0, 0
+	}
+	b.requests += requests
+	b.accepts += accepts
+}
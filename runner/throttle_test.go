@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// 服务端以非过载状态码作答的请求算作被接受, 只有过载信号与无应答才算拒绝
+func TestServerAccepted(t *testing.T) {
+	tests := []struct {
+		status string
+		err    error
+		want   bool
+	}{
+		{StatusSuccess, nil, true},
+		{StatusFailed, &StatusError{Code: 404}, true},
+		{StatusFailed, &StatusError{Code: 401}, true},
+		{StatusFailed, &StatusError{Code: 429}, false},
+		{StatusFailed, &StatusError{Code: 503}, false},
+		{StatusTimeout, context.DeadlineExceeded, false},
+		{StatusFailed, errors.New("connection refused"), false},
+	}
+	for _, tt := range tests {
+		if got := serverAccepted(tt.status, tt.err); got != tt.want {
+			t.Errorf("serverAccepted(%s, %v) = %v, 期望 %v", tt.status, tt.err, got, tt.want)
+		}
+	}
+}
+
+// 被本地限流拒绝的尝试未发出, 不计入 Attempts, 也不进入耗时统计
+func TestThrottledAttempts(t *testing.T) {
+	throttle := NewAdaptiveThrottle(0.01) // 极小的倍数: 首个被拒绝的请求之后几乎全部在本地拒绝
+	r := &Runner[int]{Config: Config{Concurrency: 1, Throttle: throttle}}
+	tasks := make([]Task[int], 50)
+	for i := range tasks {
+		tasks[i] = Task[int]{URL: "http://busy.example/", Do: func(ctx context.Context) (int, error) {
+			return 0, &StatusError{Code: 503}
+		}}
+	}
+	results := r.Run(tasks)
+
+	sent, throttled := 0, 0
+	for i, res := range results {
+		switch res.Status {
+		case StatusFailed:
+			sent += res.Attempts
+		case StatusThrottled:
+			throttled++
+			if res.Attempts != 0 || !errors.Is(res.Err, ErrThrottled) {
+				t.Errorf("#%d 被限流, Attempts = %d, 错误 %v", i, res.Attempts, res.Err)
+			}
+		default:
+			t.Errorf("#%d 状态 %s", i, res.Status)
+		}
+	}
+	if throttled == 0 {
+		t.Fatal("没有请求被本地限流")
+	}
+	if stats := ComputeStats(results); stats.Count != sent {
+		t.Errorf("Stats.Count = %d, 期望只统计发出的 %d 个请求", stats.Count, sent)
+	}
+}
@@ -0,0 +1,13 @@
+package runner
+
+import "context"
+
+// Tracer 为每次运行和其中的每个任务创建追踪区间(span), 用于接入 OpenTelemetry 等追踪系统,
+// runner 包本身不依赖任何追踪库。StartTask 返回的 ctx 会传给任务的 Do,
+// 任务内部发出的请求可以据此成为任务 span 的子 span
+type Tracer interface {
+	// StartRun 在运行开始时调用, 返回的结束函数在运行返回前以运行错误调用
+	StartRun(ctx context.Context, tasks int) (context.Context, func(err error))
+	// StartTask 在任务开始执行时调用, 返回的结束函数以最终的执行次数、状态和错误调用
+	StartTask(ctx context.Context, index int, url string) (context.Context, func(attempts int, status string, err error))
+}
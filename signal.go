@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// errInterrupted 是收到 SIGINT/SIGTERM 时取消运行的原因
+var errInterrupted = errors.New("收到中断信号")
+
+// exitInterrupted 是被信号中断时的退出码(128 + SIGINT)
+const exitInterrupted = 130
+
+// withSignals 返回收到 SIGINT/SIGTERM 时取消的 ctx: 第一次收到信号时停止分发新请求,
+// 运行中的请求按 -drain 收尾后仍输出已完成部分的报告; 第二次收到信号时立即退出
+func withSignals(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-ch:
+			fmt.Fprintf(os.Stderr, "\n收到 %v, 停止分发新请求并等待运行中的请求完成(再次中断立即退出)\n", sig)
+			cancel(fmt.Errorf("%w: %v", errInterrupted, sig))
+		case <-ctx.Done():
+			return
+		}
+		<-ch
+		os.Exit(exitInterrupted)
+	}()
+	return ctx, func() {
+		signal.Stop(ch)
+		cancel(nil)
+	}
+}
+
+// exitCode 在运行被信号中断时把退出码改为 exitInterrupted, 否则原样返回 code
+func exitCode(ctx context.Context, code int) int {
+	if errors.Is(context.Cause(ctx), errInterrupted) {
+		return exitInterrupted
+	}
+	return code
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+// tlsAudit 是对一个 HTTPS 主机证书链的检查结果
+type tlsAudit struct {
+	Chain    []*x509.Certificate // 服务端发送的证书链, 首个为叶证书
+	Expiry   time.Time           // 链中最早的过期时间
+	Problems []string
+}
+
+// tlsTarget 从 URL 中取出 HTTPS 主机的 host:port 与校验用的主机名, 非 HTTPS 返回 false
+func tlsTarget(rawURL string) (addr, name string, ok bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" {
+		return "", "", false
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port), u.Hostname(), true
+}
+
+// 已不安全的签名算法
+var weakSignatures = map[x509.SignatureAlgorithm]bool{
+	x509.MD2WithRSA:    true,
+	x509.MD5WithRSA:    true,
+	x509.SHA1WithRSA:   true,
+	x509.DSAWithSHA1:   true,
+	x509.ECDSAWithSHA1: true,
+}
+
+// auditTLSTask 构造检查 addr 证书链的任务: 握手时不校验, 拿到完整链后再逐项检查,
+// 这样即使证书无效也能报告具体原因
+func auditTLSTask(addr, name string, warnWithin time.Duration) runner.Task[tlsAudit] {
+	return runner.Task[tlsAudit]{
+		URL: "https://" + addr,
+		Do: func(ctx context.Context) (tlsAudit, error) {
+			dialer := &tls.Dialer{Config: &tls.Config{ServerName: name, InsecureSkipVerify: true}}
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return tlsAudit{}, err
+			}
+			defer conn.Close()
+
+			chain := conn.(*tls.Conn).ConnectionState().PeerCertificates
+			if len(chain) == 0 {
+				return tlsAudit{}, fmt.Errorf("%s 未提供证书", addr)
+			}
+			return checkChain(chain, name, time.Now(), warnWithin), nil
+		},
+	}
+}
+
+// checkChain 检查证书链的过期时间、签名算法、密钥长度、主机名与信任链
+func checkChain(chain []*x509.Certificate, name string, now time.Time, warnWithin time.Duration) tlsAudit {
+	a := tlsAudit{Chain: chain}
+	leaf := chain[0]
+
+	intermediates := x509.NewCertPool()
+	for _, c := range chain[1:] {
+		intermediates.AddCert(c)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{Intermediates: intermediates, CurrentTime: now}); err != nil {
+		var hostErr x509.HostnameError
+		if !errors.As(err, &hostErr) {
+			a.Problems = append(a.Problems, "信任链校验失败: "+err.Error())
+		}
+	}
+	if err := leaf.VerifyHostname(name); err != nil {
+		a.Problems = append(a.Problems, fmt.Sprintf("主机名不匹配: 证书适用于 %s", strings.Join(leaf.DNSNames, ", ")))
+	}
+
+	for i, c := range chain {
+		if a.Expiry.IsZero() || c.NotAfter.Before(a.Expiry) {
+			a.Expiry = c.NotAfter
+		}
+		label := fmt.Sprintf("证书 #%d (%s)", i, c.Subject.CommonName)
+		switch left := c.NotAfter.Sub(now); {
+		case left <= 0:
+			a.Problems = append(a.Problems, fmt.Sprintf("%s 已于 %s 过期", label, c.NotAfter.Format(time.DateOnly)))
+		case left <= warnWithin:
+			a.Problems = append(a.Problems, fmt.Sprintf("%s 将在 %d 天后过期", label, int(left.Hours()/24)))
+		}
+		if weakSignatures[c.SignatureAlgorithm] {
+			a.Problems = append(a.Problems, fmt.Sprintf("%s 使用弱签名算法 %v", label, c.SignatureAlgorithm))
+		}
+		if key, ok := c.PublicKey.(*rsa.PublicKey); ok && key.N.BitLen() < 2048 {
+			a.Problems = append(a.Problems, fmt.Sprintf("%s 的 RSA 密钥仅 %d 位", label, key.N.BitLen()))
+		}
+	}
+	return a
+}
+
+// runTLSAudit 对输入中所有不同的 HTTPS 主机并发检查证书, 输出报告并返回进程退出码
+func runTLSAudit(ctx context.Context, cfg runner.Config, urls []string, warnWithin time.Duration, out io.Writer) int {
+	var tasks []runner.Task[tlsAudit]
+	seen := make(map[string]bool)
+	for _, u := range urls {
+		addr, name, ok := tlsTarget(u)
+		if !ok || seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		tasks = append(tasks, auditTLSTask(addr, name, warnWithin))
+	}
+	if len(tasks) == 0 {
+		fmt.Fprintln(os.Stderr, "输入中没有 HTTPS 地址")
+		return 2
+	}
+
+	r := &runner.Runner[tlsAudit]{Config: cfg}
+	results, err := r.RunWithContext(ctx, tasks)
+
+	fmt.Fprintln(out, "\n======================= TLS 证书审计 =======================")
+	fmt.Fprintf(out, "%-40s %-12s %-8s %s\n", "主机", "最早过期", "剩余天数", "问题")
+	fmt.Fprintln(out, "----------------------------------------------------------------------")
+
+	var healthy, withProblems, unreachable int
+	for _, res := range results {
+		host := strings.TrimPrefix(res.URL, "https://")
+		if res.Err != nil {
+			unreachable++
+			fmt.Fprintf(out, "%-40s %-12s %-8s ❌ %v\n", host, "-", "-", res.Err)
+			continue
+		}
+		a := res.Value
+		days := int(time.Until(a.Expiry).Hours() / 24)
+		if len(a.Problems) == 0 {
+			healthy++
+			fmt.Fprintf(out, "%-40s %-12s %-8d ✅\n", host, a.Expiry.Format(time.DateOnly), days)
+			continue
+		}
+		withProblems++
+		fmt.Fprintf(out, "%-40s %-12s %-8d ⚠️ %s\n", host, a.Expiry.Format(time.DateOnly), days, a.Problems[0])
+		for _, p := range a.Problems[1:] {
+			fmt.Fprintf(out, "%-40s %-12s %-8s    %s\n", "", "", "", p)
+		}
+	}
+
+	fmt.Fprintln(out, "\n======================= 审计统计 =======================")
+	fmt.Fprintf(out, "主机数: %d, 正常 %d, 有问题 %d, 无法连接 %d\n", len(results), healthy, withProblems, unreachable)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⛔ %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// urlSpec 是输入中的一行: 可选的请求方法与请求头, 加上请求地址
+type urlSpec struct {
+	Method string      // 为空时使用 -method
+	URL    string      // 请求地址
+	Header http.Header // 覆盖公共请求头中的同名键, 为 nil 时只使用公共请求头
+}
+
+// loadURLSpecs 从文件读取请求列表, path 为 "-" 时读取标准输入
+func loadURLSpecs(path string) ([]urlSpec, error) {
+	if path == "-" {
+		return parseURLSpecs(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseURLSpecs(f)
+}
+
+// parseURLSpecs 逐行解析请求列表, 每行一个请求, 空行与 # 开头的行忽略。
+// 行格式为 [METHOD] URL [Name:value ...], URL 也可以是由 -env 解析的逻辑地址, 例如:
+//
+//	https://example.com/a
+//	POST https://example.com/b Content-Type:application/json X-Token:abc
+//	POST payments:/charge
+func parseURLSpecs(r io.Reader) ([]urlSpec, error) {
+	var specs []urlSpec
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		var spec urlSpec
+		if !isURL(fields[0]) && len(fields) > 1 {
+			spec.Method, fields = strings.ToUpper(fields[0]), fields[1:]
+		}
+		spec.URL, fields = fields[0], fields[1:]
+		if !isURL(spec.URL) {
+			return nil, fmt.Errorf("第 %d 行: %q 不是有效的请求地址", line, spec.URL)
+		}
+		for _, h := range fields {
+			name, value, ok := strings.Cut(h, ":")
+			if !ok || name == "" {
+				return nil, fmt.Errorf("第 %d 行: 请求头 %q 应为 Name:value 形式", line, h)
+			}
+			if spec.Header == nil {
+				spec.Header = make(http.Header)
+			}
+			spec.Header.Add(name, value)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, scanner.Err()
+}
+
+// isURL 判断 s 是完整地址或 "服务名:/路径" 形式的逻辑地址
+func isURL(s string) bool {
+	if strings.Contains(s, "://") {
+		return true
+	}
+	_, _, ok := logicalService(s)
+	return ok
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/abnerCrack/go-routine/runner"
+)
+
+func TestParseURLSpecs(t *testing.T) {
+	input := `# 注释与空行忽略
+
+https://example.com/a
+POST https://example.com/b Content-Type:application/json X-Tag:1 X-Tag:2
+get payments:/charge User-Agent:line-agent
+`
+	specs, err := parseURLSpecs(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []urlSpec{
+		{URL: "https://example.com/a"},
+		{Method: "POST", URL: "https://example.com/b", Header: http.Header{
+			"Content-Type": {"application/json"},
+			"X-Tag":        {"1", "2"},
+		}},
+		{Method: "GET", URL: "payments:/charge", Header: http.Header{"User-Agent": {"line-agent"}}},
+	}
+	if !reflect.DeepEqual(specs, want) {
+		t.Fatalf("解析结果 %+v, 期望 %+v", specs, want)
+	}
+
+	for _, bad := range []string{"POST not-a-url", "https://example.com/ NoColon"} {
+		if _, err := parseURLSpecs(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q 应解析失败", bad)
+		}
+	}
+}
+
+// 行内请求头覆盖公共请求头中的同名键, 其余公共请求头保留, 原执行器不变
+func TestURLSpecOverridesCommonHeader(t *testing.T) {
+	specs, err := parseURLSpecs(strings.NewReader("https://example.com/ User-Agent:line-agent X-Tag:a X-Tag:b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := &runner.HTTPExecutor{Header: http.Header{
+		"User-Agent": {"common-agent"},
+		"X-Tag":      {"common"},
+		"Accept":     {"*/*"},
+	}}
+	exec := base.With(specs[0].Method, specs[0].Header)
+
+	want := http.Header{
+		"User-Agent": {"line-agent"},
+		"X-Tag":      {"a", "b"},
+		"Accept":     {"*/*"},
+	}
+	if !reflect.DeepEqual(exec.Header, want) {
+		t.Errorf("请求头 %v, 期望 %v", exec.Header, want)
+	}
+	if got := base.Header.Values("User-Agent"); len(got) != 1 || got[0] != "common-agent" {
+		t.Errorf("原执行器的请求头被修改: %v", base.Header)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+)
+
+// 构建信息, 发布时通过 -ldflags "-X main.version=v1.2.3 -X main.commit=... -X main.date=..." 注入;
+// 未注入时从模块构建信息(go install 的模块版本、vcs.revision、vcs.time)中读取
+var (
+	version = ""
+	commit  = ""
+	date    = ""
+)
+
+// buildInfo 汇总后的版本信息
+type buildInfo struct {
+	Version, Commit, Date, GoVersion string
+}
+
+func readBuildInfo() buildInfo {
+	info := buildInfo{Version: version, Commit: commit, Date: date, GoVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && bi.Main.Version != "" {
+			info.Version = bi.Main.Version
+		}
+		var revision string
+		var dirty bool
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				revision = s.Value
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = s.Value
+				}
+			case "vcs.modified":
+				dirty = s.Value == "true"
+			}
+		}
+		if info.Commit == "" && revision != "" {
+			info.Commit = revision
+			if dirty {
+				info.Commit += "-dirty"
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "(devel)"
+	}
+	return info
+}
+
+// userAgent 返回默认的 User-Agent, 让服务端日志能识别本工具的流量
+func userAgent() string {
+	return "go-routine/" + readBuildInfo().Version
+}
+
+// printVersion 输出 version 子命令的内容
+func printVersion(w io.Writer) {
+	info := readBuildInfo()
+	fmt.Fprintf(w, "go-routine %s\n", info.Version)
+	fmt.Fprintf(w, "提交: %s\n", orUnknown(info.Commit))
+	fmt.Fprintf(w, "构建时间: %s\n", orUnknown(info.Date))
+	fmt.Fprintf(w, "Go: %s %s/%s\n", info.GoVersion, runtime.GOOS, runtime.GOARCH)
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "未知"
+	}
+	return s
+}